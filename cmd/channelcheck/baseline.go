@@ -0,0 +1,83 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// Baseline formats accepted by -baseline-format.
+const (
+	// baselineJSON is a JSON report, as written by -output=json.
+	baselineJSON = "json"
+	// baselineText lists one fingerprint per line, sorted, so baseline
+	// changes diff cleanly in review.
+	baselineText = "text"
+)
+
+// baselineFormat returns the format of the baseline at path: format when it
+// is set, otherwise text for a .txt file and JSON for anything else.
+func baselineFormat(path, format string) string {
+	if format != "" {
+		return format
+	}
+	if strings.EqualFold(filepath.Ext(path), ".txt") {
+		return baselineText
+	}
+	return baselineJSON
+}
+
+// loadBaseline reads the fingerprints of the issues accepted in a baseline.
+func loadBaseline(path, format string) (map[string]bool, error) {
+	if baselineFormat(path, format) == baselineText {
+		f, err := os.Open(path)
+		if err != nil {
+			return nil, fmt.Errorf("error reading baseline: %w", err)
+		}
+		defer f.Close()
+
+		fingerprints, err := readFingerprints(f)
+		if err != nil {
+			return nil, fmt.Errorf("error reading baseline %s: %w", path, err)
+		}
+		return fingerprints, nil
+	}
+
+	issues, err := loadReport(path)
+	if err != nil {
+		return nil, fmt.Errorf("error loading baseline: %w", err)
+	}
+	fingerprints := make(map[string]bool, len(issues))
+	for _, issue := range issues {
+		fingerprints[issue.Fingerprint()] = true
+	}
+	return fingerprints, nil
+}
+
+// writeBaseline writes issues to path as a baseline in the given format.
+func writeBaseline(path, format string, issues []Issue) error {
+	if baselineFormat(path, format) == baselineJSON {
+		return writeJSONFile(path, issues)
+	}
+
+	fingerprints := make([]string, len(issues))
+	for i, issue := range issues {
+		fingerprints[i] = issue.Fingerprint()
+	}
+	slices.Sort(fingerprints)
+	fingerprints = slices.Compact(fingerprints)
+
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("error creating baseline: %w", err)
+	}
+	for _, fingerprint := range fingerprints {
+		if _, err := fmt.Fprintln(f, fingerprint); err != nil {
+			return errors.Join(fmt.Errorf("error writing baseline: %w", err), f.Close())
+		}
+	}
+	return f.Close()
+}
@@ -0,0 +1,151 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestBaselineRoundTrip(t *testing.T) {
+	issues := []Issue{
+		{Rule: RuleSendWithoutSelect, Pos: Position{Filename: "a.go", StartLine: 4}, Message: "send", Severity: "WARNING"},
+		{Rule: RuleUnbufferedChannel, Pos: Position{Filename: "a.go", StartLine: 3}, Message: "make", Severity: "INFO"},
+	}
+	want := map[string]bool{issues[0].Fingerprint(): true, issues[1].Fingerprint(): true}
+
+	for _, tt := range []struct{ file, format string }{
+		{"baseline.json", ""},
+		{"baseline.txt", ""},
+		{"baseline", baselineText},
+		{"baseline.txt", baselineJSON},
+	} {
+		path := filepath.Join(t.TempDir(), tt.file)
+		if err := writeBaseline(path, tt.format, issues); err != nil {
+			t.Fatalf("%s (%s): write failed: %v", tt.file, tt.format, err)
+		}
+		got, err := loadBaseline(path, tt.format)
+		if err != nil {
+			t.Fatalf("%s (%s): load failed: %v", tt.file, tt.format, err)
+		}
+		if len(got) != len(want) || !got[issues[0].Fingerprint()] || !got[issues[1].Fingerprint()] {
+			t.Errorf("%s (%s): got %v, want %v", tt.file, tt.format, got, want)
+		}
+	}
+}
+
+func TestWriteBaseline_TextSorted(t *testing.T) {
+	issues := []Issue{
+		{Pos: Position{Filename: "b.go", StartLine: 1}, Message: "b", Severity: "INFO"},
+		{Pos: Position{Filename: "a.go", StartLine: 1}, Message: "a", Severity: "INFO"},
+		{Pos: Position{Filename: "a.go", StartLine: 1}, Message: "a", Severity: "INFO"},
+	}
+	path := filepath.Join(t.TempDir(), "baseline.txt")
+	if err := writeBaseline(path, "", issues); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	lines := strings.Fields(string(data))
+	want := []string{issues[0].Fingerprint(), issues[1].Fingerprint()}
+	slices.Sort(want)
+	if !slices.Equal(lines, want) {
+		t.Errorf("got %q, want sorted unique fingerprints %q", lines, want)
+	}
+}
+
+func TestRun_Baseline(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "a.go", `package test
+func send() {
+	ch := make(chan int)
+	ch <- 1
+}
+`)
+
+	report := func(args ...string) JSONOutput {
+		t.Helper()
+		var stdout bytes.Buffer
+		args = append([]string{"-path", dir, "-output", "json"}, args...)
+		if _, err := run(args, &stdout, &bytes.Buffer{}); err != nil {
+			t.Fatalf("run failed: %v", err)
+		}
+		var output JSONOutput
+		if err := json.Unmarshal(stdout.Bytes(), &output); err != nil {
+			t.Fatalf("failed to parse output: %v", err)
+		}
+		return output
+	}
+
+	jsonBaseline := filepath.Join(t.TempDir(), "baseline.json")
+	textBaseline := filepath.Join(t.TempDir(), "baseline.txt")
+	if all := report("-write-baseline", jsonBaseline); all.Total != 2 {
+		t.Fatalf("got %d issues, want 2", all.Total)
+	}
+	report("-write-baseline", textBaseline)
+
+	writeFile(t, dir, "b.go", `package test
+func other() {
+	ch := make(chan int, 1)
+	ch <- 1
+}
+`)
+	fromJSON := report("-baseline", jsonBaseline)
+	fromText := report("-baseline", textBaseline)
+	if fromJSON.Total != 1 || !strings.HasSuffix(fromJSON.Issues[0].Position.Filename, "b.go") {
+		t.Errorf("JSON baseline: got %+v, want only the issue in b.go", fromJSON.Issues)
+	}
+	if !slices.Equal(fromText.Issues, fromJSON.Issues) {
+		t.Errorf("text baseline reported %+v, JSON baseline %+v", fromText.Issues, fromJSON.Issues)
+	}
+
+	if _, err := run([]string{"-path", dir, "-baseline-format", "yaml"}, &bytes.Buffer{}, &bytes.Buffer{}); err == nil {
+		t.Errorf("expected an error for an unknown baseline format")
+	}
+}
+
+func TestRun_BaselineStrict(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "a.go", `package test
+func send(ch chan int) {
+	ch <- 1
+}
+`)
+	baseline := filepath.Join(t.TempDir(), "baseline.txt")
+	if _, err := run([]string{"-path", dir, "-write-baseline", baseline}, &bytes.Buffer{}, &bytes.Buffer{}); err != nil {
+		t.Fatalf("run failed: %v", err)
+	}
+
+	args := []string{"-path", dir, "-fail-on", "none", "-baseline", baseline, "-baseline-strict"}
+	code, err := run(args, &bytes.Buffer{}, &bytes.Buffer{})
+	if err != nil {
+		t.Fatalf("run failed: %v", err)
+	}
+	if code != 0 {
+		t.Errorf("only baselined issues: got exit code %d, want 0", code)
+	}
+
+	// An INFO issue is below every -fail-on threshold here but is new.
+	writeFile(t, dir, "b.go", `package test
+func make2() chan int {
+	return make(chan int)
+}
+`)
+	code, err = run(args, &bytes.Buffer{}, &bytes.Buffer{})
+	if err != nil {
+		t.Fatalf("run failed: %v", err)
+	}
+	if code != exitFailure {
+		t.Errorf("new issue: got exit code %d, want %d", code, exitFailure)
+	}
+
+	if _, err := run([]string{"-path", dir, "-baseline-strict"}, &bytes.Buffer{}, &bytes.Buffer{}); err == nil {
+		t.Errorf("expected an error for -baseline-strict without -baseline")
+	}
+}
@@ -0,0 +1,99 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"go/token"
+	"io"
+	"io/fs"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strings"
+	"time"
+)
+
+// BenchResult summarizes a -bench-corpus run.
+type BenchResult struct {
+	Files      int
+	Skipped    int
+	Issues     int
+	ByRule     map[string]int
+	Duration   time.Duration
+	AllocBytes uint64
+	HeapBytes  uint64
+}
+
+// benchCorpus analyzes every Go file below dir and measures the run. Files
+// that fail to parse are skipped rather than aborting the run, since large
+// corpora such as $GOROOT/src contain intentionally broken test inputs.
+func benchCorpus(dir string) (*BenchResult, error) {
+	analyzer := &Analyzer{
+		fset: token.NewFileSet(),
+	}
+	result := &BenchResult{}
+
+	runtime.GC()
+	var before runtime.MemStats
+	runtime.ReadMemStats(&before)
+	start := time.Now()
+
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || !strings.HasSuffix(path, ".go") {
+			return nil
+		}
+		if err := analyzer.analyzeFile(context.Background(), path); err != nil {
+			result.Skipped++
+			return nil
+		}
+		result.Files++
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error walking corpus: %w", err)
+	}
+
+	result.Duration = time.Since(start)
+	var after runtime.MemStats
+	runtime.ReadMemStats(&after)
+	result.AllocBytes = after.TotalAlloc - before.TotalAlloc
+	result.HeapBytes = after.HeapAlloc
+
+	result.Issues = len(analyzer.issues)
+	result.ByRule = countByRule(analyzer.issues)
+	return result, nil
+}
+
+func printBench(w io.Writer, result *BenchResult) error {
+	filesPerSec := 0.0
+	if seconds := result.Duration.Seconds(); seconds > 0 {
+		filesPerSec = float64(result.Files) / seconds
+	}
+
+	lines := []string{
+		fmt.Sprintf("Files:    %d (%d skipped)", result.Files, result.Skipped),
+		fmt.Sprintf("Issues:   %d", result.Issues),
+		fmt.Sprintf("Duration: %s (%.1f files/sec)", result.Duration.Round(time.Millisecond), filesPerSec),
+		fmt.Sprintf("Memory:   %.1f MiB allocated, %.1f MiB heap in use", mebibytes(result.AllocBytes), mebibytes(result.HeapBytes)),
+		"Issues by rule:",
+	}
+
+	rules := make([]string, 0, len(result.ByRule))
+	for rule := range result.ByRule {
+		rules = append(rules, rule)
+	}
+	slices.Sort(rules)
+	for _, rule := range rules {
+		lines = append(lines, fmt.Sprintf("  %-24s %d", rule, result.ByRule[rule]))
+	}
+
+	_, err := fmt.Fprintln(w, strings.Join(lines, "\n"))
+	return err
+}
+
+func mebibytes(b uint64) float64 {
+	return float64(b) / (1 << 20)
+}
@@ -0,0 +1,45 @@
+package main
+
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestBenchCorpus(t *testing.T) {
+	result, err := benchCorpus(filepath.Join("testdata", "corpus"))
+	if err != nil {
+		t.Fatalf("benchCorpus failed: %v", err)
+	}
+
+	if result.Files != 2 || result.Skipped != 1 {
+		t.Errorf("got %d files (%d skipped), want 2 (1 skipped)", result.Files, result.Skipped)
+	}
+	if result.Issues != 4 {
+		t.Errorf("got %d issues, want 4", result.Issues)
+	}
+	want := map[string]int{
+		RuleUnbufferedChannel: 1,
+		RuleSendWithoutSelect: 2,
+		RuleIndexedSend:       1,
+	}
+	for rule, count := range want {
+		if result.ByRule[rule] != count {
+			t.Errorf("got %d %s issues, want %d", result.ByRule[rule], rule, count)
+		}
+	}
+	if result.Duration <= 0 {
+		t.Errorf("expected a positive duration")
+	}
+
+	var out bytes.Buffer
+	if err := printBench(&out, result); err != nil {
+		t.Fatalf("printBench failed: %v", err)
+	}
+	for _, line := range []string{"Files:    2 (1 skipped)", "Issues:   4", "files/sec", "MiB allocated", "indexed-send"} {
+		if !strings.Contains(out.String(), line) {
+			t.Errorf("output missing %q:\n%s", line, out.String())
+		}
+	}
+}
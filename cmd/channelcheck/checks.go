@@ -0,0 +1,3182 @@
+package main
+
+import (
+	"fmt"
+	"go/ast"
+	"go/constant"
+	"go/token"
+	"go/types"
+	"path"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// defaultBufferBytesThreshold is the default buffer size in bytes above
+// which a buffered channel is reported.
+const defaultBufferBytesThreshold = 1 << 20
+
+// defaultLogFuncs are the calls treated as logging by the log-only-default
+// rule, as path.Match patterns over the called expression.
+var defaultLogFuncs = []string{"log.*", "slog.*", "fmt.Print*"}
+
+// checkFile runs the checks that need to see a whole file at once.
+func (a *Analyzer) checkFile(file *ast.File) {
+	goroutines := goroutineFuncs(file)
+	for _, decl := range file.Decls {
+		a.checkProducerClose(decl)
+		a.checkUnreachableChannel(decl)
+		a.checkReturnedUnbuffered(decl)
+		a.checkUncoordinatedFanIn(decl)
+		a.checkUnusedChanParam(decl, goroutines)
+		a.checkReaderLeak(decl)
+		a.checkLenBuffer(decl)
+		a.checkAtomicMix(decl)
+		a.checkLoopVarCapture(decl)
+		a.checkOverCapacity(decl)
+		a.checkUnreadErrors(decl)
+		a.checkNilFieldSend(decl)
+		a.checkChanAsSlice(decl)
+		a.checkImmediateClose(decl)
+		a.checkUnawaitedReply(decl)
+		a.checkSendBeforeGo(decl)
+	}
+	a.checkMainDeadlock(file)
+}
+
+// checkSend runs the send statement checks beyond checkChannelSend.
+func (a *Analyzer) checkSend(node *ast.SendStmt) {
+	a.checkSendUnderLock(node)
+	a.checkIndexedSend(node)
+	a.checkSendAfterHelperClose(node)
+	a.checkConditionalMake(node)
+	a.checkSendInRecover(node)
+	a.checkCapturedReassignedChan(node)
+	a.checkNilCheckFallthrough(node)
+	a.checkSendContainsChan(node)
+	a.checkNilReceiverSend(node)
+	a.checkHandlerSend(node)
+	a.checkOnceBlocking(node)
+	a.checkPackageChan(node, node.Chan)
+	a.checkShadowedCaseSend(node)
+}
+
+// checkSelect runs the checks that apply to a whole select statement.
+func (a *Analyzer) checkSelect(node *ast.SelectStmt) {
+	a.checkSingleCaseSelect(node)
+	a.checkSilentDrop(node)
+	a.checkLogOnlyDefault(node)
+	a.checkSiblingClose(node)
+	a.checkDropHandler(node)
+	if a.enclosingLoop() != nil {
+		a.checkTimeAfterInLoop(node)
+		a.checkBusyRetry(node)
+	}
+}
+
+// checkSingleCaseSelect flags a select with exactly one case and no default,
+// which behaves exactly like the plain channel operation in that case.
+// Receives get a tailored message, since a timeout case is often what was
+// meant.
+func (a *Analyzer) checkSingleCaseSelect(node *ast.SelectStmt) {
+	if len(node.Body.List) != 1 {
+		return
+	}
+	clause, ok := node.Body.List[0].(*ast.CommClause)
+	if !ok || clause.Comm == nil {
+		return
+	}
+
+	message := "single-case select without default is equivalent to a plain channel operation"
+	if ch := commRecvExpr(clause.Comm); ch != nil {
+		message = fmt.Sprintf("single-case select on a receive is just a blocking receive — use <-%s directly, or add a timeout case", types.ExprString(ch))
+	}
+	a.addIssue(Issue{
+		Rule:     RuleSingleCaseSelect,
+		Pos:      a.getPosition(node.Pos(), node.End()),
+		Message:  message,
+		Severity: "INFO",
+	})
+}
+
+// checkBusyRetry flags `for { select { case ch <- x: ...; default: } }`. With
+// an empty default the loop retries the send as fast as it can, pinning a CPU
+// until a receiver shows up.
+func (a *Analyzer) checkBusyRetry(node *ast.SelectStmt) {
+	hasSend := false
+	var emptyDefault *ast.CommClause
+	for _, stmt := range node.Body.List {
+		clause, ok := stmt.(*ast.CommClause)
+		if !ok {
+			continue
+		}
+		switch {
+		case clause.Comm == nil:
+			if len(clause.Body) == 0 {
+				emptyDefault = clause
+			}
+		case isSendStmt(clause.Comm):
+			hasSend = true
+		}
+	}
+	if !hasSend || emptyDefault == nil {
+		return
+	}
+
+	a.addIssue(Issue{
+		Rule:     RuleBusyRetry,
+		Pos:      a.getPosition(node.Pos(), node.End()),
+		Message:  "busy retry of channel send without backoff",
+		Severity: "WARNING",
+	})
+}
+
+func isSendStmt(stmt ast.Stmt) bool {
+	_, ok := stmt.(*ast.SendStmt)
+	return ok
+}
+
+// checkTimeAfterInLoop flags `case <-time.After(d)` in a select that runs on
+// every loop iteration. Each iteration creates a fresh timer, so the timeout
+// only fires if a single iteration waits for the whole duration.
+func (a *Analyzer) checkTimeAfterInLoop(node *ast.SelectStmt) {
+	for _, stmt := range node.Body.List {
+		clause, ok := stmt.(*ast.CommClause)
+		if !ok {
+			continue
+		}
+		call, ok := commRecvExpr(clause.Comm).(*ast.CallExpr)
+		if !ok || !isPkgCall(call, "time", "After") {
+			continue
+		}
+		a.addIssue(Issue{
+			Rule:     RuleTimeAfterInLoop,
+			Pos:      a.getPosition(call.Pos(), call.End()),
+			Message:  "time.After inside loop restarts the deadline every iteration — likely not intended as an overall timeout",
+			Severity: "INFO",
+		})
+	}
+}
+
+// enclosingLoop returns the innermost for or range statement containing the
+// current node, without crossing a function boundary.
+func (a *Analyzer) enclosingLoop() ast.Stmt {
+	// The last entry is the node being visited, so start at its parent.
+	for i := len(a.stack.nodes) - 2; i >= 0; i-- {
+		switch parent := a.stack.nodes[i].(type) {
+		case *ast.ForStmt:
+			return parent
+		case *ast.RangeStmt:
+			return parent
+		case *ast.FuncLit, *ast.FuncDecl:
+			return nil
+		}
+	}
+	return nil
+}
+
+// commRecvExpr returns the channel expression received from in a select case
+// such as `case <-ch:` or `case v, ok := <-ch:`, or nil if the case is not a
+// receive.
+func commRecvExpr(comm ast.Stmt) ast.Expr {
+	var expr ast.Expr
+	switch stmt := comm.(type) {
+	case *ast.ExprStmt:
+		expr = stmt.X
+	case *ast.AssignStmt:
+		if len(stmt.Rhs) == 1 {
+			expr = stmt.Rhs[0]
+		}
+	}
+	recv, ok := ast.Unparen(expr).(*ast.UnaryExpr)
+	if !ok || recv.Op != token.ARROW {
+		return nil
+	}
+	return recv.X
+}
+
+// isPkgCall reports whether call is a call to pkg.name, e.g. time.After.
+func isPkgCall(call *ast.CallExpr, pkg, name string) bool {
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok || sel.Sel.Name != name {
+		return false
+	}
+	ident, ok := sel.X.(*ast.Ident)
+	return ok && ident.Name == pkg
+}
+
+// checkSendUnderLock flags sends made between a mu.Lock() and the matching
+// mu.Unlock() in the same function. If the receiver needs the same lock
+// before it can receive, neither side can make progress.
+func (a *Analyzer) checkSendUnderLock(node *ast.SendStmt) {
+	held := make(map[string]bool)
+	for _, stmts := range a.precedingStmts() {
+		for _, stmt := range stmts {
+			call := lockCall(stmt)
+			if call == nil {
+				continue
+			}
+			sel, ok := call.Fun.(*ast.SelectorExpr)
+			if !ok {
+				continue
+			}
+			mutex := types.ExprString(sel.X)
+			switch sel.Sel.Name {
+			case "Lock", "RLock":
+				held[mutex] = true
+			case "Unlock", "RUnlock":
+				held[mutex] = false
+			}
+		}
+	}
+
+	for _, locked := range held {
+		if locked {
+			a.addIssue(Issue{
+				Rule:     RuleSendUnderLock,
+				Pos:      a.getPosition(node.Pos(), node.End()),
+				Message:  "channel send while holding a lock may deadlock",
+				Severity: "WARNING",
+			})
+			return
+		}
+	}
+}
+
+// lockCall returns the call in a statement of the form `x.Lock()` or
+// `x.Unlock()`. Deferred unlocks return nil since the lock stays held until
+// the function returns.
+func lockCall(stmt ast.Stmt) *ast.CallExpr {
+	expr, ok := stmt.(*ast.ExprStmt)
+	if !ok {
+		return nil
+	}
+	call, ok := expr.X.(*ast.CallExpr)
+	if !ok || len(call.Args) != 0 {
+		return nil
+	}
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok {
+		return nil
+	}
+	switch sel.Sel.Name {
+	case "Lock", "RLock", "Unlock", "RUnlock":
+		return call
+	}
+	return nil
+}
+
+// precedingStmts returns, for each statement list enclosing the current node
+// within its function, the statements that run before the one containing the
+// node. Lists are ordered from the outermost block inwards.
+func (a *Analyzer) precedingStmts() [][]ast.Stmt {
+	nodes := a.stack.nodes
+	start := max(a.enclosingFuncIndex(), 0)
+
+	var result [][]ast.Stmt
+	for i := start; i < len(nodes)-1; i++ {
+		var list []ast.Stmt
+		switch parent := nodes[i].(type) {
+		case *ast.BlockStmt:
+			list = parent.List
+		case *ast.CaseClause:
+			list = parent.Body
+		case *ast.CommClause:
+			list = parent.Body
+		default:
+			continue
+		}
+		for j, stmt := range list {
+			if stmt == nodes[i+1] {
+				result = append(result, list[:j])
+				break
+			}
+		}
+	}
+	return result
+}
+
+// enclosingFuncIndex returns the parent stack index of the innermost function
+// declaration or literal containing the current node, or -1 if there is none.
+func (a *Analyzer) enclosingFuncIndex() int {
+	for i := len(a.stack.nodes) - 2; i >= 0; i-- {
+		switch a.stack.nodes[i].(type) {
+		case *ast.FuncLit, *ast.FuncDecl:
+			return i
+		}
+	}
+	return -1
+}
+
+// checkForStmt runs the checks that apply to a for statement.
+func (a *Analyzer) checkForStmt(node *ast.ForStmt) {
+	a.checkSpinningDrain(node)
+	a.checkReceiveInLoopCond(node)
+}
+
+// checkReceiveInLoopCond flags `for <-ready { ... }` and other loop
+// conditions containing a receive, which block on every iteration.
+func (a *Analyzer) checkReceiveInLoopCond(node *ast.ForStmt) {
+	if node.Cond == nil {
+		return
+	}
+
+	var recv *ast.UnaryExpr
+	ast.Inspect(node.Cond, func(n ast.Node) bool {
+		if recv != nil {
+			return false
+		}
+		switch expr := n.(type) {
+		case *ast.FuncLit:
+			return false
+		case *ast.UnaryExpr:
+			if expr.Op == token.ARROW {
+				recv = expr
+				return false
+			}
+		}
+		return true
+	})
+	if recv == nil {
+		return
+	}
+
+	a.addIssue(Issue{
+		Rule:     RuleReceiveInLoopCond,
+		Pos:      a.getPosition(recv.Pos(), recv.End()),
+		Message:  "channel receive in for-loop condition blocks every iteration",
+		Severity: "INFO",
+	})
+}
+
+// checkSpinningDrain flags `for { <-ch }`. Once ch is closed every receive
+// returns the zero value immediately and the loop spins forever.
+func (a *Analyzer) checkSpinningDrain(node *ast.ForStmt) {
+	if node.Init != nil || node.Cond != nil || node.Post != nil || len(node.Body.List) != 1 {
+		return
+	}
+	expr, ok := node.Body.List[0].(*ast.ExprStmt)
+	if !ok {
+		return
+	}
+	recv, ok := ast.Unparen(expr.X).(*ast.UnaryExpr)
+	if !ok || recv.Op != token.ARROW {
+		return
+	}
+
+	a.addIssue(Issue{
+		Rule:     RuleSpinningDrain,
+		Pos:      a.getPosition(recv.Pos(), recv.End()),
+		Message:  "draining loop spins after channel close — use range or comma-ok",
+		Severity: "WARNING",
+	})
+}
+
+// checkBufferSize flags make(chan T, N) with a literal N whose buffer
+// reserves more than the configured number of bytes up front.
+func (a *Analyzer) checkBufferSize(node *ast.CallExpr, chanType *ast.ChanType) {
+	lit, ok := node.Args[1].(*ast.BasicLit)
+	if !ok || lit.Kind != token.INT {
+		return
+	}
+	capacity, err := strconv.ParseInt(lit.Value, 0, 64)
+	if err != nil {
+		return
+	}
+	elem := a.typeOf(chanType.Value)
+	if elem == nil {
+		return
+	}
+
+	threshold := a.bufferBytesThreshold
+	if threshold == 0 {
+		threshold = defaultBufferBytesThreshold
+	}
+	reserved := sizes.Sizeof(elem) * capacity
+	if reserved <= threshold {
+		return
+	}
+
+	a.addIssue(Issue{
+		Rule:     RuleLargeBuffer,
+		Pos:      a.getPosition(node.Pos(), node.End()),
+		Message:  fmt.Sprintf("channel buffer reserves ~%d bytes — consider a pointer element or smaller buffer", reserved),
+		Severity: "INFO",
+	})
+}
+
+// checkIndexedSend flags `chans[i] <- x` and `m[k] <- x`. A missing map entry
+// or unset slice element is a nil channel, which blocks forever, and the
+// collection itself often needs synchronization.
+func (a *Analyzer) checkIndexedSend(node *ast.SendStmt) {
+	if _, ok := a.chanOperand(node.Chan).(*ast.IndexExpr); !ok {
+		return
+	}
+
+	a.addIssue(Issue{
+		Rule:     RuleIndexedSend,
+		Pos:      a.getPosition(node.Pos(), node.End()),
+		Message:  "sending on a channel from a collection — ensure the entry is initialized and access is synchronized",
+		Severity: "INFO",
+	})
+}
+
+// closingFuncs returns the functions declared in file that close one of
+// their parameters, mapped to the indexes of the parameters they close.
+func closingFuncs(file *ast.File) map[string][]int {
+	closers := make(map[string][]int)
+	for _, decl := range file.Decls {
+		fn, ok := decl.(*ast.FuncDecl)
+		if !ok || fn.Recv != nil || fn.Body == nil {
+			continue
+		}
+
+		params := make(map[string]int)
+		index := 0
+		for _, field := range fn.Type.Params.List {
+			for _, name := range field.Names {
+				params[name.Name] = index
+				index++
+			}
+			if len(field.Names) == 0 {
+				index++
+			}
+		}
+
+		closed := make(map[int]bool)
+		ast.Inspect(fn.Body, func(n ast.Node) bool {
+			if _, ok := n.(*ast.FuncLit); ok {
+				return false
+			}
+			if ident := closeArg(n); ident != nil {
+				if i, ok := params[ident.Name]; ok && !closed[i] {
+					closed[i] = true
+					closers[fn.Name.Name] = append(closers[fn.Name.Name], i)
+				}
+			}
+			return true
+		})
+	}
+	return closers
+}
+
+// closeArg returns the identifier passed to close if n is a call of the form
+// close(ident), or nil otherwise.
+func closeArg(n ast.Node) *ast.Ident {
+	call, ok := n.(*ast.CallExpr)
+	if !ok || len(call.Args) != 1 {
+		return nil
+	}
+	if fun, ok := call.Fun.(*ast.Ident); !ok || fun.Name != "close" {
+		return nil
+	}
+	ident, _ := ast.Unparen(call.Args[0]).(*ast.Ident)
+	return ident
+}
+
+// checkSendAfterHelperClose flags a send on a channel that was earlier in the
+// function passed to a helper declared in the same file that closes it.
+// Sending on a closed channel panics.
+func (a *Analyzer) checkSendAfterHelperClose(node *ast.SendStmt) {
+	if len(a.closers) == 0 {
+		return
+	}
+	target := types.ExprString(a.chanOperand(node.Chan))
+
+	closedBy := ""
+	for _, stmts := range a.precedingStmts() {
+		for _, stmt := range stmts {
+			if assignsTo(stmt, target) {
+				closedBy = ""
+				continue
+			}
+			expr, ok := stmt.(*ast.ExprStmt)
+			if !ok {
+				continue
+			}
+			call, ok := expr.X.(*ast.CallExpr)
+			if !ok {
+				continue
+			}
+			fun, ok := call.Fun.(*ast.Ident)
+			if !ok {
+				continue
+			}
+			for _, i := range a.closers[fun.Name] {
+				if i < len(call.Args) && types.ExprString(a.chanOperand(call.Args[i])) == target {
+					closedBy = fun.Name
+				}
+			}
+		}
+	}
+	if closedBy == "" {
+		return
+	}
+
+	a.addIssue(Issue{
+		Rule:     RuleSendAfterClose,
+		Pos:      a.getPosition(node.Pos(), node.End()),
+		Message:  fmt.Sprintf("send on channel already closed by %s() will panic", closedBy),
+		Severity: "WARNING",
+	})
+}
+
+// assignsTo reports whether stmt assigns a new value to the expression
+// written as target.
+func assignsTo(stmt ast.Stmt, target string) bool {
+	assign, ok := stmt.(*ast.AssignStmt)
+	if !ok {
+		return false
+	}
+	for _, lhs := range assign.Lhs {
+		if types.ExprString(lhs) == target {
+			return true
+		}
+	}
+	return false
+}
+
+// enclosingFuncBody returns the body of the innermost function containing the
+// current node, or nil.
+func (a *Analyzer) enclosingFuncBody() *ast.BlockStmt {
+	i := a.enclosingFuncIndex()
+	if i < 0 {
+		return nil
+	}
+	switch fn := a.stack.nodes[i].(type) {
+	case *ast.FuncDecl:
+		return fn.Body
+	case *ast.FuncLit:
+		return fn.Body
+	}
+	return nil
+}
+
+// isChanMake reports whether expr is a make(chan T, ...) call.
+func isChanMake(expr ast.Expr) bool {
+	call, ok := ast.Unparen(expr).(*ast.CallExpr)
+	if !ok || len(call.Args) == 0 {
+		return false
+	}
+	fun, ok := call.Fun.(*ast.Ident)
+	if !ok || fun.Name != "make" {
+		return false
+	}
+	_, ok = call.Args[0].(*ast.ChanType)
+	return ok
+}
+
+// isConditional reports whether n only runs on some paths through its parent.
+func isConditional(n ast.Node) bool {
+	switch n.(type) {
+	case *ast.IfStmt, *ast.SwitchStmt, *ast.TypeSwitchStmt, *ast.SelectStmt, *ast.ForStmt, *ast.RangeStmt:
+		return true
+	}
+	return false
+}
+
+// checkConditionalMake flags a send on a local channel variable whose only
+// make before the send happens inside a branch that does not also contain the
+// send. On the other paths the variable is still nil, and sending on a nil
+// channel blocks forever.
+func (a *Analyzer) checkConditionalMake(node *ast.SendStmt) {
+	target, ok := a.chanOperand(node.Chan).(*ast.Ident)
+	if !ok {
+		return
+	}
+	body := a.enclosingFuncBody()
+	if body == nil {
+		return
+	}
+
+	conditionalMake, unconditional := false, false
+	var stack []ast.Node
+	ast.Inspect(body, func(n ast.Node) bool {
+		if n == nil {
+			stack = stack[:len(stack)-1]
+			return true
+		}
+		if n.Pos() >= node.Pos() {
+			return false
+		}
+		if _, ok := n.(*ast.FuncLit); ok {
+			return false
+		}
+		stack = append(stack, n)
+
+		var values []ast.Expr
+		switch stmt := n.(type) {
+		case *ast.AssignStmt:
+			for i, lhs := range stmt.Lhs {
+				if ident, ok := lhs.(*ast.Ident); ok && ident.Name == target.Name {
+					if len(stmt.Rhs) == len(stmt.Lhs) {
+						values = append(values, stmt.Rhs[i])
+					} else {
+						values = append(values, nil)
+					}
+				}
+			}
+		case *ast.ValueSpec:
+			for i, name := range stmt.Names {
+				if name.Name == target.Name && i < len(stmt.Values) {
+					values = append(values, stmt.Values[i])
+				}
+			}
+		}
+
+		for _, value := range values {
+			if value == nil || !isChanMake(value) || !conditionalRelativeTo(stack, node) {
+				unconditional = true
+			} else {
+				conditionalMake = true
+			}
+		}
+		return true
+	})
+	if !conditionalMake || unconditional {
+		return
+	}
+
+	a.addIssue(Issue{
+		Rule:     RuleConditionalMake,
+		Pos:      a.getPosition(node.Pos(), node.End()),
+		Message:  "channel may be nil on some paths before this send",
+		Severity: "WARNING",
+	})
+}
+
+// conditionalRelativeTo reports whether the last node of stack sits inside a
+// conditional statement that does not also contain target.
+func conditionalRelativeTo(stack []ast.Node, target ast.Node) bool {
+	for _, n := range stack[:len(stack)-1] {
+		if isConditional(n) && (target.Pos() < n.Pos() || target.Pos() >= n.End()) {
+			return true
+		}
+	}
+	return false
+}
+
+// goroutineBody records the channels a `go func() { ... }()` body sends on
+// and closes, keyed by their expression.
+type goroutineBody struct {
+	sends  map[string]bool
+	closes map[string][]*ast.CallExpr
+}
+
+// goroutineBodies returns the channel use of every goroutine started with a
+// function literal inside n. Nested function literals are attributed to
+// their own goroutine, if any, rather than the enclosing one.
+func goroutineBodies(n ast.Node) []goroutineBody {
+	var bodies []goroutineBody
+	ast.Inspect(n, func(n ast.Node) bool {
+		stmt, ok := n.(*ast.GoStmt)
+		if !ok {
+			return true
+		}
+		lit, ok := stmt.Call.Fun.(*ast.FuncLit)
+		if !ok {
+			return true
+		}
+
+		body := goroutineBody{
+			sends:  make(map[string]bool),
+			closes: make(map[string][]*ast.CallExpr),
+		}
+		ast.Inspect(lit.Body, func(n ast.Node) bool {
+			switch node := n.(type) {
+			case *ast.FuncLit:
+				return false
+			case *ast.SendStmt:
+				body.sends[types.ExprString(node.Chan)] = true
+			case *ast.CallExpr:
+				if ident := closeArg(node); ident != nil {
+					body.closes[ident.Name] = append(body.closes[ident.Name], node)
+				}
+			}
+			return true
+		})
+		bodies = append(bodies, body)
+		return true
+	})
+	return bodies
+}
+
+// checkProducerClose flags close(ch) inside a goroutine when two or more
+// goroutines started in the same declaration send on ch. Only the last
+// sender can safely close, and nothing orders the closer after the others,
+// so a send may hit the closed channel and panic.
+func (a *Analyzer) checkProducerClose(decl ast.Decl) {
+	bodies := goroutineBodies(decl)
+	if len(bodies) < 2 {
+		return
+	}
+
+	for _, body := range bodies {
+		for ch, calls := range body.closes {
+			senders := 0
+			for _, other := range bodies {
+				if other.sends[ch] {
+					senders++
+				}
+			}
+			if senders < 2 {
+				continue
+			}
+			for _, call := range calls {
+				a.addIssue(Issue{
+					Rule:     RuleProducerClose,
+					Pos:      a.getPosition(call.Pos(), call.End()),
+					Message:  "multiple goroutines send and one closes — close may race with sends",
+					Severity: "WARNING",
+				})
+			}
+		}
+	}
+}
+
+// chanMakes returns the make(chan ...) calls assigned to the variable name in
+// body, and whether name is ever assigned anything other than a make.
+func chanMakes(body *ast.BlockStmt, name string) ([]*ast.CallExpr, bool) {
+	var makes []*ast.CallExpr
+	other := false
+	record := func(value ast.Expr) {
+		if value != nil && isChanMake(value) {
+			makes = append(makes, ast.Unparen(value).(*ast.CallExpr))
+		} else {
+			other = true
+		}
+	}
+
+	ast.Inspect(body, func(n ast.Node) bool {
+		switch stmt := n.(type) {
+		case *ast.AssignStmt:
+			for i, lhs := range stmt.Lhs {
+				if ident, ok := lhs.(*ast.Ident); ok && ident.Name == name {
+					if len(stmt.Rhs) == len(stmt.Lhs) {
+						record(stmt.Rhs[i])
+					} else {
+						record(nil)
+					}
+				}
+			}
+		case *ast.ValueSpec:
+			for i, ident := range stmt.Names {
+				if ident.Name == name && i < len(stmt.Values) {
+					record(stmt.Values[i])
+				}
+			}
+		}
+		return true
+	})
+	return makes, other
+}
+
+// isUnbufferedMake reports whether a make(chan ...) call creates an
+// unbuffered channel.
+func isUnbufferedMake(call *ast.CallExpr) bool {
+	if len(call.Args) == 1 {
+		return true
+	}
+	lit, ok := call.Args[1].(*ast.BasicLit)
+	return ok && lit.Kind == token.INT && lit.Value == "0"
+}
+
+// isLocalUnbuffered reports whether the variable name is only ever assigned
+// unbuffered channels made in body.
+func isLocalUnbuffered(body *ast.BlockStmt, name string) bool {
+	makes, other := chanMakes(body, name)
+	if other || len(makes) == 0 {
+		return false
+	}
+	for _, call := range makes {
+		if !isUnbufferedMake(call) {
+			return false
+		}
+	}
+	return true
+}
+
+// checkSilentDrop flags `select { case ch <- x: default: }` on a channel
+// created unbuffered in the same function. Unless a receiver is blocked at
+// that exact moment the value is dropped, and the empty default hides it.
+func (a *Analyzer) checkSilentDrop(node *ast.SelectStmt) {
+	var sends []*ast.SendStmt
+	emptyDefault := false
+	for _, stmt := range node.Body.List {
+		clause, ok := stmt.(*ast.CommClause)
+		if !ok {
+			continue
+		}
+		if clause.Comm == nil {
+			emptyDefault = len(clause.Body) == 0
+			continue
+		}
+		if send, ok := clause.Comm.(*ast.SendStmt); ok {
+			sends = append(sends, send)
+		}
+	}
+	if !emptyDefault {
+		return
+	}
+	body := a.enclosingFuncBody()
+	if body == nil {
+		return
+	}
+
+	for _, send := range sends {
+		ident, ok := a.chanOperand(send.Chan).(*ast.Ident)
+		if !ok || !isLocalUnbuffered(body, ident.Name) {
+			continue
+		}
+		a.addIssue(Issue{
+			Rule:     RuleSilentDrop,
+			Pos:      a.getPosition(send.Pos(), send.End()),
+			Message:  "non-blocking send on unbuffered channel may silently drop values",
+			Severity: "INFO",
+		})
+	}
+}
+
+// enclosingFuncName returns the name of the function declaration containing
+// the current node, as Name or Type.Method, or "" outside any declaration.
+func (a *Analyzer) enclosingFuncName() string {
+	for _, node := range a.stack.nodes {
+		decl, ok := node.(*ast.FuncDecl)
+		if !ok {
+			continue
+		}
+		if decl.Recv == nil || len(decl.Recv.List) == 0 {
+			return decl.Name.Name
+		}
+		recv := decl.Recv.List[0].Type
+		if star, ok := recv.(*ast.StarExpr); ok {
+			recv = star.X
+		}
+		if index, ok := recv.(*ast.IndexExpr); ok {
+			recv = index.X
+		} else if index, ok := recv.(*ast.IndexListExpr); ok {
+			recv = index.X
+		}
+		return types.ExprString(recv) + "." + decl.Name.Name
+	}
+	return ""
+}
+
+// fieldPath returns the selector path of expr, such as s.events, when it
+// selects a struct field rather than a package-level name.
+func (a *Analyzer) fieldPath(expr ast.Expr) string {
+	sel, ok := ast.Unparen(expr).(*ast.SelectorExpr)
+	if !ok {
+		return ""
+	}
+	if ident, ok := sel.X.(*ast.Ident); ok && a.info != nil {
+		if _, ok := a.info.Uses[ident].(*types.PkgName); ok {
+			return ""
+		}
+	}
+	return types.ExprString(sel)
+}
+
+// isNotOk reports whether cond is `!ok` or `ok == false` for the identifier
+// named ok.
+func isNotOk(cond ast.Expr, ok string) bool {
+	isOk := func(expr ast.Expr) bool {
+		ident, isIdent := ast.Unparen(expr).(*ast.Ident)
+		return isIdent && ident.Name == ok
+	}
+	isFalse := func(expr ast.Expr) bool {
+		ident, isIdent := ast.Unparen(expr).(*ast.Ident)
+		return isIdent && ident.Name == "false"
+	}
+
+	switch cond := ast.Unparen(cond).(type) {
+	case *ast.UnaryExpr:
+		return cond.Op == token.NOT && isOk(cond.X)
+	case *ast.BinaryExpr:
+		return cond.Op == token.EQL &&
+			(isOk(cond.X) && isFalse(cond.Y) || isFalse(cond.X) && isOk(cond.Y))
+	}
+	return false
+}
+
+// checkInvertedCommaOk flags `if v, ok := <-ch; !ok { ... v ... }`. The
+// branch only runs once ch is closed, when v is the zero value, so reading it
+// there treats the zero value as real data.
+func (a *Analyzer) checkInvertedCommaOk(node *ast.IfStmt) {
+	init, ok := node.Init.(*ast.AssignStmt)
+	if !ok || len(init.Lhs) != 2 || len(init.Rhs) != 1 {
+		return
+	}
+	if recv, ok := ast.Unparen(init.Rhs[0]).(*ast.UnaryExpr); !ok || recv.Op != token.ARROW {
+		return
+	}
+	value, ok := init.Lhs[0].(*ast.Ident)
+	if !ok || value.Name == "_" {
+		return
+	}
+	okIdent, ok := init.Lhs[1].(*ast.Ident)
+	if !ok || !isNotOk(node.Cond, okIdent.Name) {
+		return
+	}
+
+	var obj types.Object
+	if a.info != nil {
+		obj = a.info.Defs[value]
+	}
+	var use *ast.Ident
+	ast.Inspect(node.Body, func(n ast.Node) bool {
+		ident, ok := n.(*ast.Ident)
+		if !ok || use != nil || ident.Name != value.Name {
+			return use == nil
+		}
+		if obj == nil || a.info.Uses[ident] == obj {
+			use = ident
+		}
+		return false
+	})
+	if use == nil {
+		return
+	}
+
+	a.addIssue(Issue{
+		Rule:     RuleInvertedCommaOk,
+		Pos:      a.getPosition(use.Pos(), use.End()),
+		Message:  "using value received from a closed channel",
+		Severity: "INFO",
+	})
+}
+
+// checkUnreachableChannel flags a channel made in a function whose every use
+// is inside an `if false { ... }` block, such as a select that was disabled
+// while debugging. Only the literal false condition is considered dead.
+func (a *Analyzer) checkUnreachableChannel(decl ast.Decl) {
+	fn, ok := decl.(*ast.FuncDecl)
+	if !ok || fn.Body == nil || a.info == nil {
+		return
+	}
+
+	var dead []*ast.BlockStmt
+	ast.Inspect(fn.Body, func(n ast.Node) bool {
+		if stmt, ok := n.(*ast.IfStmt); ok {
+			if cond, ok := ast.Unparen(stmt.Cond).(*ast.Ident); ok && cond.Name == "false" {
+				dead = append(dead, stmt.Body)
+			}
+		}
+		return true
+	})
+	if len(dead) == 0 {
+		return
+	}
+	inDead := func(pos token.Pos) bool {
+		for _, block := range dead {
+			if block.Pos() <= pos && pos < block.End() {
+				return true
+			}
+		}
+		return false
+	}
+
+	// onlyDeadUses reports whether obj is used, and only inside dead blocks.
+	onlyDeadUses := func(obj types.Object) bool {
+		used, live := false, false
+		ast.Inspect(fn.Body, func(n ast.Node) bool {
+			if ident, ok := n.(*ast.Ident); ok && a.info.Uses[ident] == obj {
+				used = true
+				live = live || !inDead(ident.Pos())
+			}
+			return !live
+		})
+		return used && !live
+	}
+
+	report := func(ident *ast.Ident, value ast.Expr) {
+		if !isChanMake(value) || inDead(ident.Pos()) {
+			return
+		}
+		obj := a.info.Defs[ident]
+		if obj == nil || !onlyDeadUses(obj) {
+			return
+		}
+		a.addIssue(Issue{
+			Rule:     RuleUnreachableChannel,
+			Pos:      a.getPosition(value.Pos(), value.End()),
+			Message:  "channel used only in unreachable code",
+			Severity: "INFO",
+		})
+	}
+
+	ast.Inspect(fn.Body, func(n ast.Node) bool {
+		switch node := n.(type) {
+		case *ast.AssignStmt:
+			if node.Tok != token.DEFINE || len(node.Lhs) != len(node.Rhs) {
+				return true
+			}
+			for i, lhs := range node.Lhs {
+				if ident, ok := lhs.(*ast.Ident); ok {
+					report(ident, node.Rhs[i])
+				}
+			}
+		case *ast.ValueSpec:
+			for i, ident := range node.Names {
+				if i < len(node.Values) {
+					report(ident, node.Values[i])
+				}
+			}
+		}
+		return true
+	})
+}
+
+// callsRecover reports whether body calls recover() outside nested function
+// literals.
+func callsRecover(body *ast.BlockStmt) bool {
+	found := false
+	ast.Inspect(body, func(n ast.Node) bool {
+		switch node := n.(type) {
+		case *ast.FuncLit:
+			return false
+		case *ast.CallExpr:
+			if fun, ok := node.Fun.(*ast.Ident); ok && fun.Name == "recover" && len(node.Args) == 0 {
+				found = true
+			}
+		}
+		return !found
+	})
+	return found
+}
+
+// checkSendInRecover flags a plain send inside `defer func() { ... recover()
+// ... }()`. After a panic the goroutine meant to read the channel may be
+// gone, and blocking here stalls the rest of the unwinding.
+func (a *Analyzer) checkSendInRecover(node *ast.SendStmt) {
+	i := a.enclosingFuncIndex()
+	if i < 2 {
+		return
+	}
+	lit, ok := a.stack.nodes[i].(*ast.FuncLit)
+	if !ok {
+		return
+	}
+	call, ok := a.stack.nodes[i-1].(*ast.CallExpr)
+	if !ok || call.Fun != lit {
+		return
+	}
+	if _, ok := a.stack.nodes[i-2].(*ast.DeferStmt); !ok || !callsRecover(lit.Body) {
+		return
+	}
+	for _, parent := range a.stack.nodes[i+1:] {
+		if _, ok := parent.(*ast.SelectStmt); ok {
+			return
+		}
+	}
+
+	a.addIssue(Issue{
+		Rule:     RuleSendInRecover,
+		Pos:      a.getPosition(node.Pos(), node.End()),
+		Message:  "channel send inside panic recovery may block cleanup",
+		Severity: "WARNING",
+	})
+}
+
+// isLogCall reports whether stmt is a call matching one of the -log-funcs
+// patterns.
+func (a *Analyzer) isLogCall(stmt ast.Stmt) bool {
+	expr, ok := stmt.(*ast.ExprStmt)
+	if !ok {
+		return false
+	}
+	call, ok := expr.X.(*ast.CallExpr)
+	if !ok {
+		return false
+	}
+	patterns := a.logFuncs
+	if patterns == nil {
+		patterns = defaultLogFuncs
+	}
+	name := types.ExprString(call.Fun)
+	for _, pattern := range patterns {
+		if matched, _ := path.Match(pattern, name); matched {
+			return true
+		}
+	}
+	return false
+}
+
+// checkLogOnlyDefault flags a select default whose body only logs. Work
+// dropped there shows up in logs but not in any metric, so capacity problems
+// go unnoticed.
+func (a *Analyzer) checkLogOnlyDefault(node *ast.SelectStmt) {
+	for _, stmt := range node.Body.List {
+		clause, ok := stmt.(*ast.CommClause)
+		if !ok || clause.Comm != nil || len(clause.Body) == 0 {
+			continue
+		}
+		logOnly := true
+		for _, stmt := range clause.Body {
+			logOnly = logOnly && a.isLogCall(stmt)
+		}
+		if !logOnly {
+			continue
+		}
+		a.addIssue(Issue{
+			Rule:     RuleLogOnlyDefault,
+			Pos:      a.getPosition(clause.Pos(), clause.Colon+1),
+			Message:  "dropped channel work is only logged, not measured",
+			Severity: "INFO",
+		})
+	}
+}
+
+// outermostFuncBody returns the body of the function declaration containing
+// the current node, so closures started from it are included, or the nearest
+// function literal's body at package level.
+func (a *Analyzer) outermostFuncBody() *ast.BlockStmt {
+	for _, node := range a.stack.nodes {
+		if decl, ok := node.(*ast.FuncDecl); ok {
+			return decl.Body
+		}
+	}
+	return a.enclosingFuncBody()
+}
+
+// isSignalChan reports whether t is a chan struct{}.
+func isSignalChan(t types.Type) bool {
+	if t == nil {
+		return false
+	}
+	ch, ok := t.Underlying().(*types.Chan)
+	if !ok {
+		return false
+	}
+	elem, ok := ch.Elem().Underlying().(*types.Struct)
+	return ok && elem.NumFields() == 0
+}
+
+// checkRangeSignal flags `for range done` over a chan struct{} that the
+// function only ever closes. Nothing is sent, so the loop body never runs and
+// the loop is just an obscure way to wait for the close.
+func (a *Analyzer) checkRangeSignal(node *ast.RangeStmt) {
+	if !isSignalChan(a.typeOf(node.X)) {
+		return
+	}
+	body := a.outermostFuncBody()
+	if body == nil {
+		return
+	}
+	target := types.ExprString(a.chanOperand(node.X))
+
+	closed, sent := false, false
+	ast.Inspect(body, func(n ast.Node) bool {
+		switch node := n.(type) {
+		case *ast.SendStmt:
+			sent = sent || types.ExprString(a.chanOperand(node.Chan)) == target
+		case *ast.CallExpr:
+			if fun, ok := node.Fun.(*ast.Ident); ok && fun.Name == "close" && len(node.Args) == 1 {
+				closed = closed || types.ExprString(a.chanOperand(node.Args[0])) == target
+			}
+		}
+		return !sent
+	})
+	if !closed || sent {
+		return
+	}
+
+	a.addIssue(Issue{
+		Rule:     RuleRangeSignal,
+		Pos:      a.getPosition(node.Pos(), node.Body.Lbrace),
+		Message:  "ranging a close-signal channel yields nothing; use a single <-" + target,
+		Severity: "INFO",
+	})
+}
+
+// checkReturnedUnbuffered flags an unbuffered make(chan T) returned from an
+// exported function, directly or through a local variable, unless the doc
+// comment mentions buffering. Callers of a library cannot tell whether
+// they must keep receiving for the producer to make progress.
+func (a *Analyzer) checkReturnedUnbuffered(decl ast.Decl) {
+	fn, ok := decl.(*ast.FuncDecl)
+	if !ok || fn.Body == nil || !fn.Name.IsExported() {
+		return
+	}
+	if fn.Doc != nil && strings.Contains(strings.ToLower(fn.Doc.Text()), "buffer") {
+		return
+	}
+
+	reported := make(map[*ast.CallExpr]bool)
+	report := func(call *ast.CallExpr) {
+		if reported[call] {
+			return
+		}
+		reported[call] = true
+		a.addIssue(Issue{
+			Rule:     RuleReturnedUnbuffered,
+			Pos:      a.getPosition(call.Pos(), call.End()),
+			Message:  "exported function returns an unbuffered channel — document or buffer",
+			Severity: "INFO",
+			Func:     fn.Name.Name,
+		})
+	}
+
+	ast.Inspect(fn.Body, func(n ast.Node) bool {
+		switch node := n.(type) {
+		case *ast.FuncLit:
+			return false
+		case *ast.ReturnStmt:
+			for _, result := range node.Results {
+				result = ast.Unparen(result)
+				if isChanMake(result) {
+					if call := result.(*ast.CallExpr); isUnbufferedMake(call) {
+						report(call)
+					}
+					continue
+				}
+				ident, ok := result.(*ast.Ident)
+				if !ok || !isLocalUnbuffered(fn.Body, ident.Name) {
+					continue
+				}
+				makes, _ := chanMakes(fn.Body, ident.Name)
+				for _, call := range makes {
+					report(call)
+				}
+			}
+		}
+		return true
+	})
+}
+
+// checkUncoordinatedFanIn flags a function that makes two or more unbuffered
+// channels, sends on them from goroutines it starts, and never closes any of
+// them. Whatever merges the channels has no way to learn that the producers
+// are done, so it or the producers tend to leak.
+func (a *Analyzer) checkUncoordinatedFanIn(decl ast.Decl) {
+	fn, ok := decl.(*ast.FuncDecl)
+	if !ok || fn.Body == nil {
+		return
+	}
+	bodies := goroutineBodies(fn)
+	if len(bodies) == 0 {
+		return
+	}
+
+	sentFromGoroutine := func(name string) bool {
+		for _, body := range bodies {
+			if body.sends[name] {
+				return true
+			}
+		}
+		return false
+	}
+
+	channels := make(map[string]bool)
+	ast.Inspect(fn.Body, func(n ast.Node) bool {
+		var names []*ast.Ident
+		switch node := n.(type) {
+		case *ast.AssignStmt:
+			for _, lhs := range node.Lhs {
+				if ident, ok := lhs.(*ast.Ident); ok {
+					names = append(names, ident)
+				}
+			}
+		case *ast.ValueSpec:
+			names = node.Names
+		}
+		for _, ident := range names {
+			if isLocalUnbuffered(fn.Body, ident.Name) && sentFromGoroutine(ident.Name) {
+				channels[ident.Name] = true
+			}
+		}
+		return true
+	})
+	if len(channels) < 2 {
+		return
+	}
+
+	closed := false
+	ast.Inspect(fn.Body, func(n ast.Node) bool {
+		if ident := closeArg(n); ident != nil && channels[ident.Name] {
+			closed = true
+		}
+		return !closed
+	})
+	if closed {
+		return
+	}
+
+	a.addIssue(Issue{
+		Rule:     RuleUncoordinatedFanIn,
+		Pos:      a.getPosition(fn.Name.Pos(), fn.Name.End()),
+		Message:  "fan-in with multiple channels lacks close coordination",
+		Severity: "INFO",
+		Func:     fn.Name.Name,
+	})
+}
+
+// outermostLoop returns the outermost for or range statement enclosing the
+// current node within its function, or nil.
+func (a *Analyzer) outermostLoop() ast.Node {
+	for _, node := range a.stack.nodes[a.enclosingFuncIndex()+1:] {
+		switch node.(type) {
+		case *ast.ForStmt, *ast.RangeStmt:
+			return node
+		}
+	}
+	return nil
+}
+
+// checkUnusedReceive flags `v = <-ch` or `v := <-ch` when v is never read
+// afterwards in the function. Either the value was meant to be used, or the
+// receive is only for synchronization and should be a bare <-ch. Variables
+// used inside closures and named results are skipped since their reads
+// cannot be ordered against the receive.
+func (a *Analyzer) checkUnusedReceive(node *ast.AssignStmt) {
+	if len(node.Lhs) != 1 || len(node.Rhs) != 1 || a.info == nil {
+		return
+	}
+	if recv, ok := ast.Unparen(node.Rhs[0]).(*ast.UnaryExpr); !ok || recv.Op != token.ARROW {
+		return
+	}
+	ident, ok := node.Lhs[0].(*ast.Ident)
+	if !ok || ident.Name == "_" {
+		return
+	}
+	obj := a.info.ObjectOf(ident)
+	body := a.enclosingFuncBody()
+	if obj == nil || body == nil || obj.Pos() < body.Pos() || obj.Pos() >= body.End() {
+		// Package-level variables, parameters and named results.
+		return
+	}
+
+	// In a loop, a read earlier in the body runs after the receive on the
+	// next iteration.
+	after := node.End()
+	if loop := a.outermostLoop(); loop != nil {
+		after = loop.Pos()
+	}
+
+	read := false
+	var stack []ast.Node
+	ast.Inspect(body, func(n ast.Node) bool {
+		if n == nil {
+			stack = stack[:len(stack)-1]
+			return true
+		}
+		stack = append(stack, n)
+		use, ok := n.(*ast.Ident)
+		if !ok || a.info.Uses[use] != obj {
+			return !read
+		}
+		for _, parent := range stack {
+			if _, ok := parent.(*ast.FuncLit); ok {
+				read = true
+			}
+		}
+		read = read || use.Pos() >= after && !isAssignTarget(stack)
+		return !read
+	})
+	if read {
+		return
+	}
+
+	a.addIssue(Issue{
+		Rule:     RuleUnusedReceive,
+		Pos:      a.getPosition(node.Pos(), node.End()),
+		Message:  "received value is never used — did you mean a bare receive?",
+		Severity: "INFO",
+	})
+}
+
+// isAssignTarget reports whether the identifier at the top of stack is
+// assigned to, rather than read, by a plain assignment.
+func isAssignTarget(stack []ast.Node) bool {
+	if len(stack) < 2 {
+		return false
+	}
+	assign, ok := stack[len(stack)-2].(*ast.AssignStmt)
+	if !ok || assign.Tok != token.ASSIGN && assign.Tok != token.DEFINE {
+		return false
+	}
+	for _, lhs := range assign.Lhs {
+		if lhs == stack[len(stack)-1] {
+			return true
+		}
+	}
+	return false
+}
+
+// checkCapturedReassignedChan flags a send inside `go func() { ... }()` on a
+// channel variable the closure captures from an enclosing loop that
+// reassigns it. Every goroutine shares the one variable, so a goroutine may
+// send on the channel of a later iteration. Passing the channel as an
+// argument gives each goroutine its own copy.
+func (a *Analyzer) checkCapturedReassignedChan(node *ast.SendStmt) {
+	ident, ok := a.chanOperand(node.Chan).(*ast.Ident)
+	if !ok || a.info == nil {
+		return
+	}
+	i := a.enclosingFuncIndex()
+	if i < 2 {
+		return
+	}
+	lit, ok := a.stack.nodes[i].(*ast.FuncLit)
+	if !ok {
+		return
+	}
+	if call, ok := a.stack.nodes[i-1].(*ast.CallExpr); !ok || call.Fun != lit {
+		return
+	}
+	if _, ok := a.stack.nodes[i-2].(*ast.GoStmt); !ok {
+		return
+	}
+	obj := a.info.Uses[ident]
+	if obj == nil || lit.Pos() <= obj.Pos() && obj.Pos() < lit.End() {
+		return
+	}
+
+	var loop ast.Node
+loops:
+	for j := i - 3; j >= 0; j-- {
+		switch parent := a.stack.nodes[j].(type) {
+		case *ast.ForStmt, *ast.RangeStmt:
+			if obj.Pos() < parent.Pos() {
+				loop = parent
+			}
+		case *ast.FuncLit, *ast.FuncDecl:
+			break loops
+		}
+	}
+	if loop == nil {
+		return
+	}
+
+	reassigned := false
+	ast.Inspect(loop, func(n ast.Node) bool {
+		if n == lit {
+			return false
+		}
+		if assign, ok := n.(*ast.AssignStmt); ok && assign.Tok == token.ASSIGN {
+			for _, lhs := range assign.Lhs {
+				if target, ok := lhs.(*ast.Ident); ok && a.info.Uses[target] == obj {
+					reassigned = true
+				}
+			}
+		}
+		return !reassigned
+	})
+	if !reassigned {
+		return
+	}
+
+	a.addIssue(Issue{
+		Rule:     RuleCapturedReassignedChan,
+		Pos:      a.getPosition(node.Pos(), node.End()),
+		Message:  fmt.Sprintf("goroutine captures channel variable %s that the loop reassigns — pass it as an argument", ident.Name),
+		Severity: "WARNING",
+	})
+}
+
+// isNilCheck reports whether cond is `target == nil` or `nil == target`.
+func isNilCheck(cond ast.Expr, target string) bool {
+	binary, ok := ast.Unparen(cond).(*ast.BinaryExpr)
+	return ok && binary.Op == token.EQL && comparesToNil(binary, target)
+}
+
+// comparesToNil reports whether binary compares target, in either operand,
+// with nil.
+func comparesToNil(binary *ast.BinaryExpr, target string) bool {
+	isNil := func(expr ast.Expr) bool {
+		ident, ok := ast.Unparen(expr).(*ast.Ident)
+		return ok && ident.Name == "nil"
+	}
+	return isNil(binary.Y) && types.ExprString(binary.X) == target ||
+		isNil(binary.X) && types.ExprString(binary.Y) == target
+}
+
+// isTerminating reports whether stmt leaves the enclosing block: a return,
+// branch, panic, os.Exit or log.Fatal call.
+func isTerminating(stmt ast.Stmt) bool {
+	switch stmt := stmt.(type) {
+	case *ast.ReturnStmt, *ast.BranchStmt:
+		return true
+	case *ast.ExprStmt:
+		call, ok := stmt.X.(*ast.CallExpr)
+		if !ok {
+			return false
+		}
+		if fun, ok := call.Fun.(*ast.Ident); ok && fun.Name == "panic" {
+			return true
+		}
+		return isPkgCall(call, "os", "Exit") || isPkgCall(call, "log", "Fatal") ||
+			isPkgCall(call, "log", "Fatalf") || isPkgCall(call, "log", "Fatalln")
+	}
+	return false
+}
+
+// checkNilCheckFallthrough flags a send on ch that follows `if ch == nil {
+// ... }` in the same block when that branch neither leaves the block nor
+// assigns ch. A nil ch then falls through to the send, and sending on a nil
+// channel blocks forever.
+func (a *Analyzer) checkNilCheckFallthrough(node *ast.SendStmt) {
+	preceding := a.precedingStmts()
+	if len(preceding) == 0 {
+		return
+	}
+	target := types.ExprString(a.chanOperand(node.Chan))
+
+	var check *ast.IfStmt
+	for _, stmt := range preceding[len(preceding)-1] {
+		if assignsTo(stmt, target) {
+			check = nil
+			continue
+		}
+		ifStmt, ok := stmt.(*ast.IfStmt)
+		if !ok || ifStmt.Else != nil || !isNilCheck(ifStmt.Cond, target) {
+			continue
+		}
+		body := ifStmt.Body.List
+		if len(body) > 0 && isTerminating(body[len(body)-1]) {
+			continue
+		}
+		assigned := false
+		ast.Inspect(ifStmt.Body, func(n ast.Node) bool {
+			if stmt, ok := n.(ast.Stmt); ok && assignsTo(stmt, target) {
+				assigned = true
+			}
+			return !assigned
+		})
+		if !assigned {
+			check = ifStmt
+		}
+	}
+	if check == nil {
+		return
+	}
+
+	a.addIssue(Issue{
+		Rule:     RuleNilCheckFallthrough,
+		Pos:      a.getPosition(node.Pos(), node.End()),
+		Message:  fmt.Sprintf("send after `if %s == nil` falls through when %s is nil — sending on a nil channel blocks forever", target, target),
+		Severity: "WARNING",
+	})
+}
+
+// goroutineFuncs returns the names of the functions started directly with
+// `go name(...)` in file.
+func goroutineFuncs(file *ast.File) map[string]bool {
+	names := make(map[string]bool)
+	ast.Inspect(file, func(n ast.Node) bool {
+		if stmt, ok := n.(*ast.GoStmt); ok {
+			if fun, ok := stmt.Call.Fun.(*ast.Ident); ok {
+				names[fun.Name] = true
+			}
+		}
+		return true
+	})
+	return names
+}
+
+// checkUnusedChanParam flags a channel parameter that is never referenced in
+// a function the file starts as a goroutine. The goroutine was handed a
+// channel it neither sends on nor receives from, which suggests the wrong
+// channel was wired up.
+func (a *Analyzer) checkUnusedChanParam(decl ast.Decl, goroutines map[string]bool) {
+	fn, ok := decl.(*ast.FuncDecl)
+	if !ok || fn.Recv != nil || fn.Body == nil || !goroutines[fn.Name.Name] {
+		return
+	}
+
+	for _, field := range fn.Type.Params.List {
+		if _, ok := field.Type.(*ast.ChanType); !ok && !isChanType(a.typeOf(field.Type)) {
+			continue
+		}
+		for _, param := range field.Names {
+			if param.Name == "_" || a.identUsed(fn.Body, param) {
+				continue
+			}
+			a.addIssue(Issue{
+				Rule:     RuleUnusedChanParam,
+				Pos:      a.getPosition(param.Pos(), param.End()),
+				Message:  "function receives a channel parameter it never uses",
+				Severity: "INFO",
+				Func:     fn.Name.Name,
+			})
+		}
+	}
+}
+
+// isChanType reports whether t is a channel type.
+func isChanType(t types.Type) bool {
+	if t == nil {
+		return false
+	}
+	_, ok := t.Underlying().(*types.Chan)
+	return ok
+}
+
+// identUsed reports whether the variable declared by ident is referenced in
+// body. Without type information any identifier of the same name counts.
+func (a *Analyzer) identUsed(body *ast.BlockStmt, ident *ast.Ident) bool {
+	var obj types.Object
+	if a.info != nil {
+		obj = a.info.Defs[ident]
+	}
+	used := false
+	ast.Inspect(body, func(n ast.Node) bool {
+		if use, ok := n.(*ast.Ident); ok && use.Name == ident.Name {
+			used = used || obj == nil || a.info.Uses[use] == obj
+		}
+		return !used
+	})
+	return used
+}
+
+// structHasChan reports whether t is a struct with a channel field, directly
+// or through nested structs and arrays.
+func structHasChan(t types.Type) bool {
+	st, ok := t.Underlying().(*types.Struct)
+	if !ok {
+		return false
+	}
+	var hasChan func(t types.Type, depth int) bool
+	hasChan = func(t types.Type, depth int) bool {
+		// Recursive struct types go through pointers, which are not
+		// followed, but bound the depth anyway.
+		if depth > 8 {
+			return false
+		}
+		switch u := t.Underlying().(type) {
+		case *types.Chan:
+			return true
+		case *types.Array:
+			return hasChan(u.Elem(), depth+1)
+		case *types.Struct:
+			for i := range u.NumFields() {
+				if hasChan(u.Field(i).Type(), depth+1) {
+					return true
+				}
+			}
+		}
+		return false
+	}
+	return hasChan(st, 0)
+}
+
+// checkSendContainsChan flags a send whose value is a struct holding
+// channels. Both sides then hold the same channels, and it is easy to lose
+// track of which one may close them.
+func (a *Analyzer) checkSendContainsChan(node *ast.SendStmt) {
+	t := a.typeOf(node.Value)
+	if t == nil || !structHasChan(t) {
+		return
+	}
+
+	a.addIssue(Issue{
+		Rule:     RuleSendContainsChan,
+		Pos:      a.getPosition(node.Value.Pos(), node.Value.End()),
+		Message:  "sending a value containing channels transfers channel ownership — ensure single ownership",
+		Severity: "INFO",
+	})
+}
+
+// checkSiblingClose flags `select { case ch <- x: ...; case <-done: close(ch) }`.
+// The select may run the closing case first and, on a later pass, the send
+// case, which then panics on the closed channel.
+func (a *Analyzer) checkSiblingClose(node *ast.SelectStmt) {
+	var sends []*ast.SendStmt
+	closed := make(map[*ast.CommClause][]string)
+	for _, stmt := range node.Body.List {
+		clause, ok := stmt.(*ast.CommClause)
+		if !ok {
+			continue
+		}
+		if send, ok := clause.Comm.(*ast.SendStmt); ok {
+			sends = append(sends, send)
+		}
+		for _, bodyStmt := range clause.Body {
+			ast.Inspect(bodyStmt, func(n ast.Node) bool {
+				call, ok := n.(*ast.CallExpr)
+				if !ok || len(call.Args) != 1 {
+					return true
+				}
+				if fun, ok := call.Fun.(*ast.Ident); ok && fun.Name == "close" {
+					closed[clause] = append(closed[clause], types.ExprString(a.chanOperand(call.Args[0])))
+				}
+				return true
+			})
+		}
+	}
+
+	for _, send := range sends {
+		target := types.ExprString(a.chanOperand(send.Chan))
+		for clause, names := range closed {
+			if clause.Comm == ast.Stmt(send) || !slices.Contains(names, target) {
+				continue
+			}
+			a.addIssue(Issue{
+				Rule:     RuleSiblingClose,
+				Pos:      a.getPosition(send.Pos(), send.End()),
+				Message:  "select may send on a channel that a sibling case closes",
+				Severity: "WARNING",
+			})
+			break
+		}
+	}
+}
+
+// checkReaderLeak flags `go func() { for { <-ch } }()` and similar goroutines
+// that block on a plain receive with nothing to stop them: no select to wait
+// on a done channel, no <-ctx.Done(), and no close of the channel anywhere in
+// the declaration. If the senders go away, the reader blocks forever.
+func (a *Analyzer) checkReaderLeak(decl ast.Decl) {
+	// Channels are keyed by expression, as in checkSiblingClose, so
+	// close(s.events) covers a reader of <-s.events.
+	closed := make(map[string]bool)
+	ast.Inspect(decl, func(n ast.Node) bool {
+		call, ok := n.(*ast.CallExpr)
+		if !ok || len(call.Args) != 1 {
+			return true
+		}
+		if fun, ok := call.Fun.(*ast.Ident); ok && fun.Name == "close" {
+			closed[types.ExprString(a.chanOperand(call.Args[0]))] = true
+		}
+		return true
+	})
+
+	ast.Inspect(decl, func(n ast.Node) bool {
+		stmt, ok := n.(*ast.GoStmt)
+		if !ok {
+			return true
+		}
+		lit, ok := stmt.Call.Fun.(*ast.FuncLit)
+		if !ok {
+			return true
+		}
+
+		blocks, cancellable := false, false
+		ast.Inspect(lit.Body, func(n ast.Node) bool {
+			switch node := n.(type) {
+			case *ast.FuncLit:
+				return false
+			case *ast.SelectStmt:
+				cancellable = true
+			case *ast.UnaryExpr:
+				if node.Op != token.ARROW {
+					break
+				}
+				operand := a.chanOperand(node.X)
+				if _, ok := operand.(*ast.CallExpr); ok {
+					// <-ctx.Done(), <-time.After(d) and the like are
+					// themselves how the goroutine gets stopped.
+					cancellable = true
+				} else if !closed[types.ExprString(operand)] {
+					blocks = true
+				}
+			}
+			return true
+		})
+		if blocks && !cancellable {
+			a.addIssue(Issue{
+				Rule:     RuleReaderLeak,
+				Pos:      a.getPosition(stmt.Pos(), stmt.End()),
+				Message:  "spawned reader goroutine has no cancellation path",
+				Severity: "WARNING",
+			})
+		}
+		return true
+	})
+}
+
+// checkFuncChan flags make(chan func(...)). Channels of callbacks are
+// legitimate, but the goroutine that runs them stops draining the channel
+// while a handler blocks, which easily turns into a deadlock.
+func (a *Analyzer) checkFuncChan(node *ast.CallExpr, chanType *ast.ChanType) {
+	if _, ok := ast.Unparen(chanType.Value).(*ast.FuncType); !ok {
+		return
+	}
+	a.addIssue(Issue{
+		Rule:     RuleFuncChan,
+		Pos:      a.getPosition(node.Pos(), node.End()),
+		Message:  "channel of functions — ensure handlers don't block the channel's goroutine",
+		Severity: "INFO",
+	})
+}
+
+// checkChanOfChan flags make(chan chan T). Channels of channels are the
+// usual shape of request/reply, but which side sends on, reads from and
+// closes the inner channels is easy to get wrong and invisible in the type.
+func (a *Analyzer) checkChanOfChan(node *ast.CallExpr, chanType *ast.ChanType) {
+	if _, ok := ast.Unparen(chanType.Value).(*ast.ChanType); !ok {
+		return
+	}
+	a.addIssue(Issue{
+		Rule:     RuleChanOfChan,
+		Pos:      a.getPosition(node.Pos(), node.End()),
+		Message:  "channel of channels — document request/reply ownership",
+		Severity: "INFO",
+	})
+}
+
+// checkNilReceiverSend flags `s.ch <- x` in a method with a pointer receiver
+// s that is never compared to nil before the send. Called on a nil *Server,
+// the field access panics before the send could even block. To limit noise,
+// any earlier s == nil or s != nil in the method counts as a guard.
+func (a *Analyzer) checkNilReceiverSend(node *ast.SendStmt) {
+	sel, ok := a.chanOperand(node.Chan).(*ast.SelectorExpr)
+	if !ok {
+		return
+	}
+	recv, ok := ast.Unparen(sel.X).(*ast.Ident)
+	if !ok {
+		return
+	}
+
+	var decl *ast.FuncDecl
+	for _, n := range a.stack.nodes {
+		if d, ok := n.(*ast.FuncDecl); ok {
+			decl = d
+			break
+		}
+	}
+	if decl == nil || decl.Body == nil || decl.Recv == nil || len(decl.Recv.List) != 1 {
+		return
+	}
+	field := decl.Recv.List[0]
+	if _, ok := field.Type.(*ast.StarExpr); !ok || len(field.Names) != 1 || field.Names[0].Name != recv.Name {
+		return
+	}
+
+	guarded := false
+	ast.Inspect(decl.Body, func(n ast.Node) bool {
+		binary, ok := n.(*ast.BinaryExpr)
+		if ok && binary.Pos() < node.Pos() && (binary.Op == token.EQL || binary.Op == token.NEQ) {
+			guarded = guarded || comparesToNil(binary, recv.Name)
+		}
+		return !guarded
+	})
+	if guarded {
+		return
+	}
+
+	a.addIssue(Issue{
+		Rule:     RuleNilReceiverSend,
+		Pos:      a.getPosition(node.Pos(), node.End()),
+		Message:  fmt.Sprintf("send on field %s of pointer receiver %s without a nil check — a nil receiver panics", types.ExprString(sel), recv.Name),
+		Severity: "INFO",
+		Func:     a.enclosingFuncName(),
+	})
+}
+
+// checkLenBuffer flags `ch := make(chan T, len(items))` followed by a range
+// over items that sends each element on ch. The send never blocks, so the
+// whole input is queued at once; a small buffer drained by a bounded pool of
+// workers keeps memory flat and gives the producer backpressure.
+func (a *Analyzer) checkLenBuffer(decl ast.Decl) {
+	makes := make(map[string]*ast.CallExpr) // channel -> make(chan T, len(x))
+	sized := make(map[string]string)        // channel -> x
+	ast.Inspect(decl, func(n ast.Node) bool {
+		assign, ok := n.(*ast.AssignStmt)
+		if !ok || len(assign.Lhs) != len(assign.Rhs) {
+			return true
+		}
+		for i, rhs := range assign.Rhs {
+			call, ok := ast.Unparen(rhs).(*ast.CallExpr)
+			if !ok || !isChanMake(call) || len(call.Args) != 2 {
+				continue
+			}
+			size, ok := ast.Unparen(call.Args[1]).(*ast.CallExpr)
+			if !ok || len(size.Args) != 1 {
+				continue
+			}
+			if fun, ok := size.Fun.(*ast.Ident); !ok || fun.Name != "len" {
+				continue
+			}
+			ch := types.ExprString(assign.Lhs[i])
+			makes[ch] = call
+			sized[ch] = types.ExprString(size.Args[0])
+		}
+		return true
+	})
+	if len(makes) == 0 {
+		return
+	}
+
+	reported := make(map[string]bool)
+	ast.Inspect(decl, func(n ast.Node) bool {
+		loop, ok := n.(*ast.RangeStmt)
+		if !ok {
+			return true
+		}
+		items := types.ExprString(loop.X)
+		ast.Inspect(loop.Body, func(n ast.Node) bool {
+			send, ok := n.(*ast.SendStmt)
+			if !ok {
+				return true
+			}
+			ch := types.ExprString(a.chanOperand(send.Chan))
+			if sized[ch] != items || reported[ch] {
+				return true
+			}
+			reported[ch] = true
+			a.addIssue(Issue{
+				Rule:     RuleLenBuffer,
+				Pos:      a.getPosition(makes[ch].Pos(), makes[ch].End()),
+				Message:  "buffering the entire input defeats backpressure — consider a bounded pool",
+				Severity: "INFO",
+			})
+			return true
+		})
+		return true
+	})
+}
+
+// checkContextValue flags context.WithValue(ctx, key, ch) where ch is a
+// channel. Context values are meant for request-scoped data; a channel hidden
+// in one is sent on by code that cannot see who receives or closes it.
+func (a *Analyzer) checkContextValue(node *ast.CallExpr) {
+	if !isPkgCall(node, "context", "WithValue") || len(node.Args) != 3 {
+		return
+	}
+	if !isChanType(a.typeOf(node.Args[2])) {
+		return
+	}
+	a.addIssue(Issue{
+		Rule:     RuleContextChan,
+		Pos:      a.getPosition(node.Pos(), node.End()),
+		Message:  "storing a channel in context is an anti-pattern",
+		Severity: "INFO",
+		Func:     a.enclosingFuncName(),
+	})
+}
+
+// checkMainDeadlock flags a bare `<-ch` statement in func main of a file that
+// never starts a goroutine. Nothing can ever send, so unless ch comes from
+// elsewhere the program deadlocks. Receives on channels handed to a call,
+// such as signal.Notify(sig, ...), or returned by one, such as ctx.Done(),
+// are skipped since the callee may send on them.
+func (a *Analyzer) checkMainDeadlock(file *ast.File) {
+	if file.Name.Name != "main" {
+		return
+	}
+	var main *ast.FuncDecl
+	hasGo := false
+	handedOff := make(map[string]bool)
+	ast.Inspect(file, func(n ast.Node) bool {
+		switch node := n.(type) {
+		case *ast.FuncDecl:
+			if node.Recv == nil && node.Name.Name == "main" {
+				main = node
+			}
+		case *ast.GoStmt:
+			hasGo = true
+		case *ast.CallExpr:
+			for _, arg := range node.Args {
+				if ident, ok := ast.Unparen(arg).(*ast.Ident); ok {
+					handedOff[ident.Name] = true
+				}
+			}
+		}
+		return true
+	})
+	if main == nil || main.Body == nil || hasGo {
+		return
+	}
+
+	ast.Inspect(main.Body, func(n ast.Node) bool {
+		switch node := n.(type) {
+		case *ast.FuncLit, *ast.SelectStmt:
+			return false
+		case *ast.ExprStmt:
+			recv, ok := ast.Unparen(node.X).(*ast.UnaryExpr)
+			if !ok || recv.Op != token.ARROW {
+				return true
+			}
+			switch x := ast.Unparen(recv.X).(type) {
+			case *ast.Ident:
+				if handedOff[x.Name] {
+					return true
+				}
+			case *ast.CallExpr:
+				if !isChanMake(x) {
+					return true
+				}
+			default:
+				return true
+			}
+			a.addIssue(Issue{
+				Rule:     RuleMainDeadlock,
+				Pos:      a.getPosition(node.Pos(), node.End()),
+				Message:  "blocking receive in main with no goroutines will deadlock",
+				Severity: "INFO",
+				Func:     "main",
+			})
+		}
+		return true
+	})
+}
+
+// checkAtomicMix flags a function that both operates on a channel and calls
+// a sync/atomic function. Coordinating goroutines through channels and
+// atomics at once splits the synchronization across two mechanisms, which
+// is hard to reason about. The rule is a heuristic, reported once per
+// function at its first atomic call.
+func (a *Analyzer) checkAtomicMix(decl ast.Decl) {
+	fn, ok := decl.(*ast.FuncDecl)
+	if !ok || fn.Body == nil {
+		return
+	}
+
+	usesChan := false
+	var atomicCall *ast.CallExpr
+	ast.Inspect(fn.Body, func(n ast.Node) bool {
+		switch node := n.(type) {
+		case *ast.SendStmt:
+			usesChan = true
+		case *ast.UnaryExpr:
+			usesChan = usesChan || node.Op == token.ARROW
+		case *ast.CallExpr:
+			if atomicCall != nil {
+				break
+			}
+			if sel, ok := node.Fun.(*ast.SelectorExpr); ok {
+				if pkg, ok := sel.X.(*ast.Ident); ok && pkg.Name == "atomic" {
+					atomicCall = node
+				}
+			}
+		}
+		return true
+	})
+	if !usesChan || atomicCall == nil {
+		return
+	}
+
+	a.addIssue(Issue{
+		Rule:     RuleAtomicMix,
+		Pos:      a.getPosition(atomicCall.Pos(), atomicCall.End()),
+		Message:  "mixing channels and atomics for coordination — pick one",
+		Severity: "INFO",
+		Func:     fn.Name.Name,
+	})
+}
+
+// checkLoopVarCapture flags a goroutine started in a loop that refers to a
+// variable declared by the loop, as in
+// `for _, job := range jobs { go func() { results <- job }() }`. Before Go
+// 1.22 all iterations share the variable, so the goroutines see whatever
+// value it has when they run. It only runs when -go selects an older version.
+func (a *Analyzer) checkLoopVarCapture(decl ast.Decl) {
+	if !a.goBefore(loopVarSemantics) {
+		return
+	}
+
+	ast.Inspect(decl, func(n ast.Node) bool {
+		var vars []*ast.Ident
+		var body *ast.BlockStmt
+		switch loop := n.(type) {
+		case *ast.RangeStmt:
+			if loop.Tok != token.DEFINE {
+				return true
+			}
+			for _, expr := range []ast.Expr{loop.Key, loop.Value} {
+				if ident, ok := expr.(*ast.Ident); ok && ident.Name != "_" {
+					vars = append(vars, ident)
+				}
+			}
+			body = loop.Body
+		case *ast.ForStmt:
+			if init, ok := loop.Init.(*ast.AssignStmt); ok && init.Tok == token.DEFINE {
+				for _, expr := range init.Lhs {
+					if ident, ok := expr.(*ast.Ident); ok && ident.Name != "_" {
+						vars = append(vars, ident)
+					}
+				}
+			}
+			body = loop.Body
+		default:
+			return true
+		}
+
+		for _, v := range vars {
+			var obj types.Object
+			if a.info != nil {
+				obj = a.info.Defs[v]
+			}
+			ast.Inspect(body, func(n ast.Node) bool {
+				stmt, ok := n.(*ast.GoStmt)
+				if !ok {
+					return true
+				}
+				lit, ok := stmt.Call.Fun.(*ast.FuncLit)
+				if !ok {
+					return true
+				}
+				captured := false
+				ast.Inspect(lit.Body, func(n ast.Node) bool {
+					ident, ok := n.(*ast.Ident)
+					if ok && ident.Name == v.Name && (obj == nil || a.info.Uses[ident] == obj) {
+						captured = true
+					}
+					return !captured
+				})
+				if captured {
+					a.addIssue(Issue{
+						Rule:     RuleLoopVarCapture,
+						Pos:      a.getPosition(stmt.Pos(), stmt.End()),
+						Message:  fmt.Sprintf("goroutine captures loop variable %s, shared by all iterations before Go 1.22 — pass it as an argument", v.Name),
+						Severity: "WARNING",
+					})
+				}
+				return true
+			})
+		}
+		return true
+	})
+}
+
+// checkOverCapacity flags `ch := make(chan int, 2); ch <- a; ch <- b; ch <- c`:
+// more sends in a row than the constant capacity, with nothing in between
+// that could receive. The send past capacity blocks forever. Tracking stops
+// at the first statement that mentions the channel other than as a send
+// target, starts a goroutine or changes control flow, so only sends that
+// are guaranteed to run back to back are counted.
+func (a *Analyzer) checkOverCapacity(decl ast.Decl) {
+	ast.Inspect(decl, func(n ast.Node) bool {
+		block, ok := n.(*ast.BlockStmt)
+		if !ok {
+			return true
+		}
+
+		capacity := make(map[string]int64) // channel -> capacity
+		sends := make(map[string]int64)    // channel -> sends so far
+		for _, stmt := range block.List {
+			if send, ok := stmt.(*ast.SendStmt); ok {
+				if ident, ok := ast.Unparen(send.Chan).(*ast.Ident); ok {
+					if limit, tracked := capacity[ident.Name]; tracked && !mentions(send.Value, ident.Name) {
+						sends[ident.Name]++
+						if sends[ident.Name] == limit+1 {
+							a.addIssue(Issue{
+								Rule:     RuleOverCapacity,
+								Pos:      a.getPosition(send.Pos(), send.End()),
+								Message:  "more guaranteed sends than channel capacity will deadlock",
+								Severity: "ERROR",
+							})
+						}
+						continue
+					}
+				}
+			}
+
+			switch stmt.(type) {
+			case *ast.AssignStmt, *ast.DeclStmt, *ast.ExprStmt, *ast.IncDecStmt, *ast.EmptyStmt, *ast.SendStmt:
+			default:
+				clear(capacity)
+				continue
+			}
+			spawns := false
+			ast.Inspect(stmt, func(n ast.Node) bool {
+				switch n.(type) {
+				case *ast.GoStmt, *ast.FuncLit:
+					spawns = true
+				}
+				return !spawns
+			})
+			if spawns {
+				clear(capacity)
+				continue
+			}
+			for ch := range capacity {
+				if mentions(stmt, ch) {
+					delete(capacity, ch)
+				}
+			}
+
+			if assign, ok := stmt.(*ast.AssignStmt); ok && len(assign.Lhs) == len(assign.Rhs) {
+				for i, rhs := range assign.Rhs {
+					ident, ok := assign.Lhs[i].(*ast.Ident)
+					call, isCall := ast.Unparen(rhs).(*ast.CallExpr)
+					if !ok || !isCall || !isChanMake(call) || len(call.Args) != 2 {
+						continue
+					}
+					lit, ok := ast.Unparen(call.Args[1]).(*ast.BasicLit)
+					if !ok || lit.Kind != token.INT {
+						continue
+					}
+					if limit, err := strconv.ParseInt(lit.Value, 0, 64); err == nil && limit > 0 {
+						capacity[ident.Name] = limit
+						sends[ident.Name] = 0
+					}
+				}
+			}
+		}
+		return true
+	})
+}
+
+// mentions reports whether n refers to the identifier name.
+func mentions(n ast.Node, name string) bool {
+	found := false
+	ast.Inspect(n, func(n ast.Node) bool {
+		if ident, ok := n.(*ast.Ident); ok && ident.Name == name {
+			found = true
+		}
+		return !found
+	})
+	return found
+}
+
+// checkUnreadErrors flags `errCh := make(chan error, n)` when the function
+// sends on errCh but never reads it. Any use other than a send, a close or
+// the make itself, such as a receive, a range, returning errCh or passing it
+// to another function, counts as a possible reader, so only channels whose
+// errors certainly vanish are reported.
+func (a *Analyzer) checkUnreadErrors(decl ast.Decl) {
+	fn, ok := decl.(*ast.FuncDecl)
+	if !ok || fn.Body == nil {
+		return
+	}
+
+	makes := make(map[string]*ast.CallExpr)
+	ast.Inspect(fn.Body, func(n ast.Node) bool {
+		assign, ok := n.(*ast.AssignStmt)
+		if !ok || assign.Tok != token.DEFINE || len(assign.Lhs) != len(assign.Rhs) {
+			return true
+		}
+		for i, rhs := range assign.Rhs {
+			ident, ok := assign.Lhs[i].(*ast.Ident)
+			call, isCall := ast.Unparen(rhs).(*ast.CallExpr)
+			if !ok || !isCall || !isChanMake(call) {
+				continue
+			}
+			if elem, ok := call.Args[0].(*ast.ChanType).Value.(*ast.Ident); ok && elem.Name == "error" {
+				makes[ident.Name] = call
+			}
+		}
+		return true
+	})
+
+	for name, call := range makes {
+		sent, read := false, false
+		ast.Inspect(fn.Body, func(n ast.Node) bool {
+			switch node := n.(type) {
+			case *ast.SendStmt:
+				if ident, ok := ast.Unparen(node.Chan).(*ast.Ident); ok && ident.Name == name {
+					sent = true
+					read = read || mentions(node.Value, name)
+					return false
+				}
+			case *ast.AssignStmt:
+				// The make itself.
+				if len(node.Lhs) == len(node.Rhs) {
+					for i, lhs := range node.Lhs {
+						if ident, ok := lhs.(*ast.Ident); ok && ident.Name == name && node.Rhs[i] == call {
+							return false
+						}
+					}
+				}
+			case *ast.CallExpr:
+				if ident := closeArg(node); ident != nil && ident.Name == name {
+					return false
+				}
+			case *ast.Ident:
+				read = read || node.Name == name
+			}
+			return true
+		})
+		if !sent || read {
+			continue
+		}
+		a.addIssue(Issue{
+			Rule:     RuleUnreadErrors,
+			Pos:      a.getPosition(call.Pos(), call.End()),
+			Message:  "errors are sent on a channel that's never read",
+			Severity: "WARNING",
+			Func:     fn.Name.Name,
+		})
+	}
+}
+
+// checkNilFieldSend flags `s := Server{}` (or &Server{...}) that leaves a
+// channel field nil, explicitly or by omission, followed by a send on
+// s.events that nothing could have made. Sending on a nil channel blocks
+// forever. Only variables that never escape are tracked: any method call on
+// the variable, any other use of it as a value and any assignment to or
+// address of the field counts as a possible make.
+func (a *Analyzer) checkNilFieldSend(decl ast.Decl) {
+	fn, ok := decl.(*ast.FuncDecl)
+	if !ok || fn.Body == nil || a.info == nil {
+		return
+	}
+
+	ast.Inspect(fn.Body, func(n ast.Node) bool {
+		assign, ok := n.(*ast.AssignStmt)
+		if !ok || assign.Tok != token.DEFINE || len(assign.Lhs) != 1 || len(assign.Rhs) != 1 {
+			return true
+		}
+		v, ok := assign.Lhs[0].(*ast.Ident)
+		if !ok {
+			return true
+		}
+		rhs := ast.Unparen(assign.Rhs[0])
+		if addr, ok := rhs.(*ast.UnaryExpr); ok && addr.Op == token.AND {
+			rhs = ast.Unparen(addr.X)
+		}
+		lit, ok := rhs.(*ast.CompositeLit)
+		if !ok {
+			return true
+		}
+		if nilFields := a.nilChanFields(lit); len(nilFields) > 0 {
+			a.reportNilFieldSends(fn, v, nilFields)
+		}
+		return true
+	})
+}
+
+// nilChanFields returns the channel fields that the struct literal lit
+// leaves nil.
+func (a *Analyzer) nilChanFields(lit *ast.CompositeLit) map[string]bool {
+	t := a.typeOf(lit)
+	if t == nil {
+		return nil
+	}
+	st, ok := t.Underlying().(*types.Struct)
+	if !ok {
+		return nil
+	}
+
+	values := make(map[string]ast.Expr)
+	for i, elt := range lit.Elts {
+		if kv, ok := elt.(*ast.KeyValueExpr); ok {
+			if key, ok := kv.Key.(*ast.Ident); ok {
+				values[key.Name] = kv.Value
+			}
+		} else if i < st.NumFields() {
+			values[st.Field(i).Name()] = elt
+		}
+	}
+
+	nilFields := make(map[string]bool)
+	for field := range st.Fields() {
+		if !isChanType(field.Type()) {
+			continue
+		}
+		value, set := values[field.Name()]
+		if ident, ok := ast.Unparen(value).(*ast.Ident); !set || ok && ident.Name == "nil" {
+			nilFields[field.Name()] = true
+		}
+	}
+	return nilFields
+}
+
+// reportNilFieldSends reports sends on v.field in fn for the fields of
+// nilFields, unless v escapes or the field may be assigned anywhere in fn.
+func (a *Analyzer) reportNilFieldSends(fn *ast.FuncDecl, v *ast.Ident, nilFields map[string]bool) {
+	obj := a.info.Defs[v]
+	if obj == nil {
+		return
+	}
+	isV := func(expr ast.Expr) bool {
+		ident, ok := ast.Unparen(expr).(*ast.Ident)
+		return ok && a.info.Uses[ident] == obj
+	}
+	// field returns the field name of v.field, or "".
+	field := func(expr ast.Expr) string {
+		sel, ok := ast.Unparen(expr).(*ast.SelectorExpr)
+		if !ok || !isV(sel.X) {
+			return ""
+		}
+		return sel.Sel.Name
+	}
+
+	escapes := false
+	var sends []*ast.SendStmt
+	ast.Inspect(fn.Body, func(n ast.Node) bool {
+		switch node := n.(type) {
+		case *ast.SendStmt:
+			if name := field(node.Chan); nilFields[name] {
+				sends = append(sends, node)
+				ast.Inspect(node.Value, func(n ast.Node) bool {
+					if ident, ok := n.(*ast.Ident); ok {
+						escapes = escapes || isV(ident)
+					}
+					return !escapes
+				})
+				return false
+			}
+		case *ast.AssignStmt:
+			for _, lhs := range node.Lhs {
+				delete(nilFields, field(lhs))
+			}
+		case *ast.UnaryExpr:
+			if node.Op == token.AND {
+				delete(nilFields, field(node.X))
+			}
+		case *ast.SelectorExpr:
+			if isV(node.X) {
+				if sel, ok := a.info.Selections[node]; !ok || sel.Kind() != types.FieldVal {
+					escapes = true
+				}
+				return false
+			}
+		case *ast.Ident:
+			escapes = escapes || a.info.Uses[node] == obj
+		}
+		return true
+	})
+	if escapes {
+		return
+	}
+
+	for _, send := range sends {
+		name := field(send.Chan)
+		if !nilFields[name] {
+			continue
+		}
+		a.addIssue(Issue{
+			Rule:     RuleNilFieldSend,
+			Pos:      a.getPosition(send.Pos(), send.End()),
+			Message:  fmt.Sprintf("send on channel field %s.%s, which the struct literal leaves nil and nothing makes, blocks forever", v.Name, name),
+			Severity: "WARNING",
+			Func:     fn.Name.Name,
+		})
+	}
+}
+
+// checkDropHandler enforces a convention that every non-blocking send,
+// `select { case ch <- x: ...; default: ... }`, reports the dropped value by
+// calling a drop handler matching -drop-handler somewhere in its default. It
+// only runs when drop handlers are configured.
+func (a *Analyzer) checkDropHandler(node *ast.SelectStmt) {
+	if len(a.dropHandlers) == 0 {
+		return
+	}
+
+	hasSend := false
+	var def *ast.CommClause
+	for _, stmt := range node.Body.List {
+		clause, ok := stmt.(*ast.CommClause)
+		if !ok {
+			continue
+		}
+		if clause.Comm == nil {
+			def = clause
+		} else {
+			hasSend = hasSend || isSendStmt(clause.Comm)
+		}
+	}
+	if !hasSend || def == nil {
+		return
+	}
+
+	handled := false
+	for _, stmt := range def.Body {
+		ast.Inspect(stmt, func(n ast.Node) bool {
+			call, ok := n.(*ast.CallExpr)
+			if !ok {
+				return !handled
+			}
+			name := types.ExprString(call.Fun)
+			for _, pattern := range a.dropHandlers {
+				if matched, _ := path.Match(pattern, name); matched {
+					handled = true
+				}
+			}
+			return !handled
+		})
+	}
+	if handled {
+		return
+	}
+
+	a.addIssue(Issue{
+		Rule:     RuleDropHandler,
+		Pos:      a.getPosition(def.Pos(), def.Colon+1),
+		Message:  fmt.Sprintf("non-blocking send drops values without calling a drop handler (%s)", strings.Join(a.dropHandlers, ", ")),
+		Severity: "WARNING",
+		Func:     a.enclosingFuncName(),
+	})
+}
+
+// checkHandlerSend flags a send in an HTTP handler, a function shaped like
+// func(http.ResponseWriter, *http.Request), that can block the request
+// goroutine: a plain send, or a select case with neither a default nor a
+// case on the request context's Done channel. If the client goes away, the
+// handler keeps waiting. Sends in goroutines the handler starts are not
+// checked, since they do not hold up the request.
+func (a *Analyzer) checkHandlerSend(node *ast.SendStmt) {
+	i := a.enclosingFuncIndex()
+	if i < 0 {
+		return
+	}
+	var ftype *ast.FuncType
+	var body *ast.BlockStmt
+	switch fn := a.stack.nodes[i].(type) {
+	case *ast.FuncDecl:
+		ftype, body = fn.Type, fn.Body
+	case *ast.FuncLit:
+		ftype, body = fn.Type, fn.Body
+	}
+	req, ok := a.handlerRequest(ftype)
+	if !ok {
+		return
+	}
+
+	n := len(a.stack.nodes)
+	if n >= 4 {
+		clause, isClause := a.stack.nodes[n-2].(*ast.CommClause)
+		sel, isSelect := a.stack.nodes[n-4].(*ast.SelectStmt)
+		if isClause && isSelect && clause.Comm == node && selectsOnRequest(sel, requestContexts(body, req)) {
+			return
+		}
+	}
+
+	a.addIssue(Issue{
+		Rule:     RuleHandlerSend,
+		Pos:      a.getPosition(node.Pos(), node.End()),
+		Message:  "blocking channel send in HTTP handler ignores request cancellation",
+		Severity: "WARNING",
+		Func:     a.enclosingFuncName(),
+	})
+}
+
+// handlerRequest returns the name of the *http.Request parameter if ftype
+// is func(http.ResponseWriter, *http.Request).
+func (a *Analyzer) handlerRequest(ftype *ast.FuncType) (string, bool) {
+	if ftype == nil || ftype.Params == nil {
+		return "", false
+	}
+	var names []string
+	var params []ast.Expr
+	for _, field := range ftype.Params.List {
+		if len(field.Names) == 0 {
+			names = append(names, "_")
+			params = append(params, field.Type)
+		}
+		for _, name := range field.Names {
+			names = append(names, name.Name)
+			params = append(params, field.Type)
+		}
+	}
+	if len(params) != 2 || !a.isHTTPType(params[0], "ResponseWriter") {
+		return "", false
+	}
+	star, ok := params[1].(*ast.StarExpr)
+	if !ok || !a.isHTTPType(star.X, "Request") {
+		return "", false
+	}
+	return names[1], true
+}
+
+// isHTTPType reports whether the type expression expr denotes net/http's
+// name. Without type information it falls back to matching http.name.
+func (a *Analyzer) isHTTPType(expr ast.Expr, name string) bool {
+	if t := a.typeOf(expr); t != nil {
+		named, ok := t.(*types.Named)
+		return ok && named.Obj().Pkg() != nil && named.Obj().Pkg().Path() == "net/http" && named.Obj().Name() == name
+	}
+	return types.ExprString(expr) == "http."+name
+}
+
+// requestContexts returns the expressions that evaluate to the context of
+// request req: req.Context() itself and the first variable assigned from any
+// call on it, such as ctx in `ctx, cancel := context.WithTimeout(r.Context(), d)`.
+func requestContexts(body *ast.BlockStmt, req string) map[string]bool {
+	contexts := map[string]bool{req + ".Context()": true}
+	if body == nil || req == "_" {
+		return contexts
+	}
+	ast.Inspect(body, func(n ast.Node) bool {
+		assign, ok := n.(*ast.AssignStmt)
+		if !ok || len(assign.Rhs) != 1 {
+			return true
+		}
+		if _, ok := assign.Rhs[0].(*ast.CallExpr); !ok || !strings.Contains(types.ExprString(assign.Rhs[0]), req+".Context()") {
+			return true
+		}
+		if ident, ok := assign.Lhs[0].(*ast.Ident); ok && ident.Name != "_" {
+			contexts[ident.Name] = true
+		}
+		return true
+	})
+	return contexts
+}
+
+// selectsOnRequest reports whether node has a default case, or a case that
+// receives from the Done channel of one of contexts.
+func selectsOnRequest(node *ast.SelectStmt, contexts map[string]bool) bool {
+	for _, stmt := range node.Body.List {
+		clause, ok := stmt.(*ast.CommClause)
+		if !ok {
+			continue
+		}
+		if clause.Comm == nil {
+			return true
+		}
+		call, ok := ast.Unparen(commRecvExpr(clause.Comm)).(*ast.CallExpr)
+		if !ok {
+			continue
+		}
+		if sel, ok := call.Fun.(*ast.SelectorExpr); ok && sel.Sel.Name == "Done" && contexts[types.ExprString(sel.X)] {
+			return true
+		}
+	}
+	return false
+}
+
+// checkChanAsSlice flags a channel used as a plain buffer in a function that
+// starts no goroutines: `ch := make(chan T, N)`, a loop that sends on ch
+// exactly N times, `close(ch)`, then `for v := range ch`. Nothing runs
+// concurrently with the sends, so a slice says the same thing more plainly.
+func (a *Analyzer) checkChanAsSlice(decl ast.Decl) {
+	fn, ok := decl.(*ast.FuncDecl)
+	if !ok || fn.Body == nil {
+		return
+	}
+	concurrent := false
+	ast.Inspect(fn.Body, func(n ast.Node) bool {
+		_, isGo := n.(*ast.GoStmt)
+		concurrent = concurrent || isGo
+		return !concurrent
+	})
+	if concurrent {
+		return
+	}
+
+	type buffer struct {
+		make     *ast.CallExpr
+		capacity int64
+		stage    int // 0: made, 1: filled, 2: closed
+	}
+	buffers := make(map[string]*buffer)
+	for _, stmt := range fn.Body.List {
+		for ch, buf := range buffers {
+			switch {
+			case buf.stage == 0 && a.loopSendCount(stmt, ch) == buf.capacity:
+				buf.stage = 1
+			case buf.stage == 1 && isCloseOf(stmt, ch):
+				buf.stage = 2
+			case buf.stage == 2 && isRangeOver(stmt, ch):
+				a.addIssue(Issue{
+					Rule:     RuleChanAsSlice,
+					Pos:      a.getPosition(buf.make.Pos(), buf.make.End()),
+					Message:  "channel used as a buffer — a slice may be clearer",
+					Severity: "INFO",
+					Func:     fn.Name.Name,
+				})
+				delete(buffers, ch)
+			case mentions(stmt, ch):
+				delete(buffers, ch)
+			}
+		}
+
+		assign, ok := stmt.(*ast.AssignStmt)
+		if !ok || len(assign.Lhs) != 1 || len(assign.Rhs) != 1 {
+			continue
+		}
+		ident, ok := assign.Lhs[0].(*ast.Ident)
+		call, isCall := ast.Unparen(assign.Rhs[0]).(*ast.CallExpr)
+		if !ok || !isCall || !isChanMake(call) || len(call.Args) != 2 {
+			continue
+		}
+		if capacity, ok := a.constInt(call.Args[1]); ok && capacity > 0 {
+			buffers[ident.Name] = &buffer{make: call, capacity: capacity}
+		}
+	}
+}
+
+// loopSendCount returns how many times stmt sends on ch if it is a loop with
+// a constant trip count, `for i := 0; i < N; i++` or `for range N`, whose
+// body sends on ch exactly once and does not otherwise use it. It returns -1
+// for anything else.
+func (a *Analyzer) loopSendCount(stmt ast.Stmt, ch string) int64 {
+	var trips int64
+	var body *ast.BlockStmt
+	switch loop := stmt.(type) {
+	case *ast.ForStmt:
+		init, ok := loop.Init.(*ast.AssignStmt)
+		cond, isBinary := loop.Cond.(*ast.BinaryExpr)
+		post, isIncDec := loop.Post.(*ast.IncDecStmt)
+		if !ok || !isBinary || !isIncDec || len(init.Lhs) != 1 || len(init.Rhs) != 1 || cond.Op != token.LSS || post.Tok != token.INC {
+			return -1
+		}
+		i, ok := init.Lhs[0].(*ast.Ident)
+		if !ok || types.ExprString(cond.X) != i.Name || types.ExprString(post.X) != i.Name {
+			return -1
+		}
+		start, ok := a.constInt(init.Rhs[0])
+		end, isConst := a.constInt(cond.Y)
+		if !ok || !isConst {
+			return -1
+		}
+		trips, body = max(end-start, 0), loop.Body
+	case *ast.RangeStmt:
+		n, ok := a.constInt(loop.X)
+		if !ok {
+			return -1
+		}
+		trips, body = n, loop.Body
+	default:
+		return -1
+	}
+
+	sends := 0
+	for _, stmt := range body.List {
+		if send, ok := stmt.(*ast.SendStmt); ok && types.ExprString(send.Chan) == ch && !mentions(send.Value, ch) {
+			sends++
+		} else if mentions(stmt, ch) {
+			return -1
+		}
+	}
+	if sends != 1 {
+		return -1
+	}
+	return trips
+}
+
+// constInt returns the value of expr if it is an integer constant.
+func (a *Analyzer) constInt(expr ast.Expr) (int64, bool) {
+	if a.info != nil {
+		if tv, ok := a.info.Types[expr]; ok && tv.Value != nil {
+			return constant.Int64Val(constant.ToInt(tv.Value))
+		}
+	}
+	lit, ok := ast.Unparen(expr).(*ast.BasicLit)
+	if !ok || lit.Kind != token.INT {
+		return 0, false
+	}
+	n, err := strconv.ParseInt(lit.Value, 0, 64)
+	return n, err == nil
+}
+
+// isCloseOf reports whether stmt is `close(ch)`.
+func isCloseOf(stmt ast.Stmt, ch string) bool {
+	expr, ok := stmt.(*ast.ExprStmt)
+	if !ok {
+		return false
+	}
+	call, ok := expr.X.(*ast.CallExpr)
+	if !ok || len(call.Args) != 1 {
+		return false
+	}
+	fun, ok := call.Fun.(*ast.Ident)
+	return ok && fun.Name == "close" && types.ExprString(call.Args[0]) == ch
+}
+
+// isRangeOver reports whether stmt is a range loop over ch.
+func isRangeOver(stmt ast.Stmt, ch string) bool {
+	loop, ok := stmt.(*ast.RangeStmt)
+	return ok && types.ExprString(loop.X) == ch
+}
+
+// checkRecvOperand flags a receive used directly as a divisor, `a / <-ch`,
+// or as an index, `items[<-ch]`. Once ch is closed the receive yields 0,
+// which divides by zero or silently selects the first element; receiving
+// with `v, ok := <-ch` first lets the code notice the close.
+func (a *Analyzer) checkRecvOperand(node *ast.UnaryExpr) {
+	var operand ast.Node = node
+	i := len(a.stack.nodes) - 2
+	for ; i >= 0; i-- {
+		paren, ok := a.stack.nodes[i].(*ast.ParenExpr)
+		if !ok {
+			break
+		}
+		operand = paren
+	}
+	if i < 0 {
+		return
+	}
+
+	hazard := false
+	switch parent := a.stack.nodes[i].(type) {
+	case *ast.BinaryExpr:
+		hazard = (parent.Op == token.QUO || parent.Op == token.REM) && parent.Y == operand
+	case *ast.IndexExpr:
+		hazard = parent.Index == operand
+	case *ast.AssignStmt:
+		hazard = (parent.Tok == token.QUO_ASSIGN || parent.Tok == token.REM_ASSIGN) && len(parent.Rhs) == 1 && parent.Rhs[0] == operand
+	}
+	if !hazard {
+		return
+	}
+
+	a.addIssue(Issue{
+		Rule:     RuleClosedRecvOperand,
+		Pos:      a.getPosition(node.Pos(), node.End()),
+		Message:  "value from channel used as divisor/index without checking closed status",
+		Severity: "INFO",
+		Func:     a.enclosingFuncName(),
+	})
+}
+
+// checkOnceBlocking flags a send or receive outside a select in a function
+// literal passed to sync.Once's Do, as in `once.Do(func() { ch <- x })`. If
+// the operation blocks, Do never returns, and every other caller of Do waits
+// on the Once forever.
+func (a *Analyzer) checkOnceBlocking(node ast.Node) {
+	i := len(a.stack.nodes) - 2
+	for ; i >= 0; i-- {
+		switch a.stack.nodes[i].(type) {
+		case *ast.SelectStmt, *ast.FuncDecl:
+			return
+		}
+		if _, ok := a.stack.nodes[i].(*ast.FuncLit); ok {
+			break
+		}
+	}
+	if i < 1 {
+		return
+	}
+	call, ok := a.stack.nodes[i-1].(*ast.CallExpr)
+	if !ok || len(call.Args) != 1 || call.Args[0] != a.stack.nodes[i] {
+		return
+	}
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok || sel.Sel.Name != "Do" || !a.isOnce(sel.X) {
+		return
+	}
+
+	a.addIssue(Issue{
+		Rule:     RuleOnceBlocking,
+		Pos:      a.getPosition(node.Pos(), node.End()),
+		Message:  "blocking channel op inside sync.Once.Do can deadlock subsequent callers",
+		Severity: "WARNING",
+		Func:     a.enclosingFuncName(),
+	})
+}
+
+// isOnce reports whether expr is a sync.Once or a pointer to one. Without
+// type information any receiver is accepted.
+func (a *Analyzer) isOnce(expr ast.Expr) bool {
+	t := a.typeOf(expr)
+	if t == nil {
+		return true
+	}
+	if ptr, ok := t.(*types.Pointer); ok {
+		t = ptr.Elem()
+	}
+	named, ok := t.(*types.Named)
+	return ok && named.Obj().Pkg() != nil && named.Obj().Pkg().Path() == "sync" && named.Obj().Name() == "Once"
+}
+
+// checkImmediateClose flags `ch := make(chan T)` directly followed by
+// `close(ch)` in a function that never sends on ch. A pre-closed channel is
+// occasionally what is wanted, as a signal that is already given, but more
+// often code later waits on it expecting the signal still to come.
+func (a *Analyzer) checkImmediateClose(decl ast.Decl) {
+	fn, ok := decl.(*ast.FuncDecl)
+	if !ok || fn.Body == nil {
+		return
+	}
+	sent := make(map[string]bool)
+	ast.Inspect(fn.Body, func(n ast.Node) bool {
+		if send, ok := n.(*ast.SendStmt); ok {
+			sent[types.ExprString(send.Chan)] = true
+		}
+		return true
+	})
+
+	ast.Inspect(fn.Body, func(n ast.Node) bool {
+		block, ok := n.(*ast.BlockStmt)
+		if !ok {
+			return true
+		}
+		for i := 1; i < len(block.List); i++ {
+			ch := madeChan(block.List[i-1])
+			if ch == "" || sent[ch] || !isCloseOf(block.List[i], ch) {
+				continue
+			}
+			a.addIssue(Issue{
+				Rule:     RuleImmediateClose,
+				Pos:      a.getPosition(block.List[i].Pos(), block.List[i].End()),
+				Message:  "channel closed immediately after creation with no sends — intended as a pre-closed signal?",
+				Severity: "INFO",
+				Func:     fn.Name.Name,
+			})
+		}
+		return true
+	})
+}
+
+// madeChan returns the name of the variable stmt assigns a new channel to,
+// in `ch := make(chan T)` or `var ch = make(chan T)`, or "" if it does not.
+func madeChan(stmt ast.Stmt) string {
+	var names []*ast.Ident
+	var values []ast.Expr
+	switch s := stmt.(type) {
+	case *ast.AssignStmt:
+		for _, lhs := range s.Lhs {
+			ident, _ := lhs.(*ast.Ident)
+			names = append(names, ident)
+		}
+		values = s.Rhs
+	case *ast.DeclStmt:
+		gen, ok := s.Decl.(*ast.GenDecl)
+		if !ok || len(gen.Specs) != 1 {
+			return ""
+		}
+		spec, ok := gen.Specs[0].(*ast.ValueSpec)
+		if !ok {
+			return ""
+		}
+		names, values = spec.Names, spec.Values
+	}
+	if len(names) != 1 || len(values) != 1 || names[0] == nil {
+		return ""
+	}
+	if call, ok := ast.Unparen(values[0]).(*ast.CallExpr); ok && isChanMake(call) {
+		return names[0].Name
+	}
+	return ""
+}
+
+// checkPackageChan notes a send or receive, node, on ch when ch is a
+// package-level variable. Any function in the package can send on, receive
+// from or close such a channel, so who owns it and when it closes is not
+// visible at the use.
+func (a *Analyzer) checkPackageChan(node ast.Node, ch ast.Expr) {
+	ident, ok := a.chanOperand(ch).(*ast.Ident)
+	if !ok || !a.isPackageVar(ident) {
+		return
+	}
+	a.addIssue(Issue{
+		Rule:     RulePackageChan,
+		Pos:      a.getPosition(node.Pos(), node.End()),
+		Message:  "operating on a package-level channel — ensure lifecycle and ownership are clear",
+		Severity: "INFO",
+		Func:     a.enclosingFuncName(),
+	})
+}
+
+// isPackageVar reports whether ident refers to a package-level variable.
+// Without type information it checks the var declarations at the top level
+// of the file being analyzed, ignoring shadowing.
+func (a *Analyzer) isPackageVar(ident *ast.Ident) bool {
+	if a.info != nil {
+		if v, ok := a.info.Uses[ident].(*types.Var); ok {
+			return v.Pkg() != nil && v.Parent() == v.Pkg().Scope()
+		}
+	}
+	if len(a.stack.nodes) == 0 {
+		return false
+	}
+	file, ok := a.stack.nodes[0].(*ast.File)
+	if !ok {
+		return false
+	}
+	for _, decl := range file.Decls {
+		gen, ok := decl.(*ast.GenDecl)
+		if !ok || gen.Tok != token.VAR {
+			continue
+		}
+		for _, spec := range gen.Specs {
+			for _, name := range spec.(*ast.ValueSpec).Names {
+				if name.Name == ident.Name {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// checkUnawaitedReply flags the request half of a request/reply exchange
+// without the reply half: a function makes an unbuffered reply channel,
+// sends a request carrying it, as in `requests <- request{reply: reply}`,
+// and never receives from reply or hands it on. The goroutine serving the
+// request then blocks forever on its reply send.
+func (a *Analyzer) checkUnawaitedReply(decl ast.Decl) {
+	fn, ok := decl.(*ast.FuncDecl)
+	if !ok || fn.Body == nil {
+		return
+	}
+	ast.Inspect(fn.Body, func(n ast.Node) bool {
+		send, ok := n.(*ast.SendStmt)
+		if !ok {
+			return true
+		}
+		value := ast.Unparen(send.Value)
+		if addr, ok := value.(*ast.UnaryExpr); ok && addr.Op == token.AND {
+			value = ast.Unparen(addr.X)
+		}
+		lit, ok := value.(*ast.CompositeLit)
+		if !ok {
+			return true
+		}
+		for _, elt := range lit.Elts {
+			if kv, ok := elt.(*ast.KeyValueExpr); ok {
+				elt = kv.Value
+			}
+			reply, ok := ast.Unparen(elt).(*ast.Ident)
+			if !ok {
+				continue
+			}
+			makes, other := chanMakes(fn.Body, reply.Name)
+			if len(makes) != 1 || other || !isUnbufferedMake(makes[0]) || awaitsReply(fn.Body, reply.Name, lit) {
+				continue
+			}
+			a.addIssue(Issue{
+				Rule:     RuleUnawaitedReply,
+				Pos:      a.getPosition(send.Pos(), send.End()),
+				Message:  "request sent but reply channel is never awaited",
+				Severity: "INFO",
+				Func:     fn.Name.Name,
+			})
+		}
+		return true
+	})
+}
+
+// awaitsReply reports whether body receives from the channel variable
+// reply, or hands it on somewhere other than the request literal req: as a
+// call argument, in a return, in another literal or by assignment.
+func awaitsReply(body *ast.BlockStmt, reply string, req *ast.CompositeLit) bool {
+	awaited := false
+	ast.Inspect(body, func(n ast.Node) bool {
+		if awaited || n == req {
+			return false
+		}
+		switch node := n.(type) {
+		case *ast.UnaryExpr:
+			awaited = node.Op == token.ARROW && mentions(node.X, reply)
+		case *ast.RangeStmt:
+			awaited = mentions(node.X, reply)
+		case *ast.CallExpr:
+			for _, arg := range node.Args {
+				awaited = awaited || mentions(arg, reply)
+			}
+		case *ast.ReturnStmt:
+			awaited = mentions(node, reply)
+		case *ast.CompositeLit:
+			awaited = mentions(node, reply)
+		case *ast.AssignStmt:
+			for _, rhs := range node.Rhs {
+				if ident, ok := ast.Unparen(rhs).(*ast.Ident); ok && ident.Name == reply {
+					awaited = true
+				}
+			}
+		}
+		return !awaited
+	})
+	return awaited
+}
+
+// checkSendBeforeGo flags `ch <- x` on a local unbuffered channel followed,
+// in the same block, by the go statement that would receive it, as in
+// `ch <- x; go consume(ch)`. Nothing else can have received from ch yet, so
+// the send blocks before the consumer starts and the function deadlocks.
+func (a *Analyzer) checkSendBeforeGo(decl ast.Decl) {
+	fn, ok := decl.(*ast.FuncDecl)
+	if !ok || fn.Body == nil {
+		return
+	}
+	ast.Inspect(fn.Body, func(n ast.Node) bool {
+		block, ok := n.(*ast.BlockStmt)
+		if !ok {
+			return true
+		}
+		for i, stmt := range block.List {
+			send, ok := stmt.(*ast.SendStmt)
+			if !ok {
+				continue
+			}
+			ch, ok := ast.Unparen(send.Chan).(*ast.Ident)
+			if !ok || !isLocalUnbuffered(fn.Body, ch.Name) || mentions(send.Value, ch.Name) {
+				continue
+			}
+			// Anything that used ch before the send, such as a goroutine
+			// or a call it was handed to, may already be receiving.
+			if usedBefore(fn.Body, ch.Name, send.Pos()) {
+				continue
+			}
+			for _, later := range block.List[i+1:] {
+				if goStmt, ok := later.(*ast.GoStmt); ok && mentions(goStmt, ch.Name) {
+					a.addIssue(Issue{
+						Rule:     RuleSendBeforeGo,
+						Pos:      a.getPosition(send.Pos(), send.End()),
+						Message:  "send precedes the goroutine meant to receive it — deadlock",
+						Severity: "ERROR",
+						Func:     fn.Name.Name,
+					})
+					break
+				}
+			}
+		}
+		return true
+	})
+}
+
+// usedBefore reports whether body uses the variable name before pos, other
+// than by assigning it a new channel.
+func usedBefore(body *ast.BlockStmt, name string, pos token.Pos) bool {
+	used := false
+	ast.Inspect(body, func(n ast.Node) bool {
+		if used || n == nil || n.Pos() >= pos {
+			return false
+		}
+		if stmt, ok := n.(ast.Stmt); ok && madeChan(stmt) == name {
+			return false
+		}
+		ident, ok := n.(*ast.Ident)
+		used = ok && ident.Name == name
+		return !used
+	})
+	return used
+}
+
+// checkShadowedCaseSend flags a send in the body of a select case on a
+// variable that the case's own receive declared, hiding an outer variable of
+// the same name, as in `case ch := <-in: ch <- x`. The send goes to the value
+// just received, not to the outer channel the name suggests.
+func (a *Analyzer) checkShadowedCaseSend(node *ast.SendStmt) {
+	target, ok := ast.Unparen(node.Chan).(*ast.Ident)
+	if !ok {
+		return
+	}
+
+	var clause *ast.CommClause
+	for i := len(a.stack.nodes) - 2; i >= 0 && clause == nil; i-- {
+		switch parent := a.stack.nodes[i].(type) {
+		case *ast.CommClause:
+			clause = parent
+		case *ast.FuncLit, *ast.FuncDecl:
+			return
+		}
+	}
+	if clause == nil {
+		return
+	}
+	assign, ok := clause.Comm.(*ast.AssignStmt)
+	if !ok || assign.Tok != token.DEFINE || commRecvExpr(assign) == nil {
+		return
+	}
+
+	for _, lhs := range assign.Lhs {
+		bound, ok := lhs.(*ast.Ident)
+		if !ok || bound.Name != target.Name || !a.shadowsOuter(bound, target) {
+			continue
+		}
+		a.addIssue(Issue{
+			Rule:     RuleShadowedCaseSend,
+			Pos:      a.getPosition(node.Pos(), node.End()),
+			Message:  "send targets a variable shadowed by this select case's receive",
+			Severity: "INFO",
+			Func:     a.enclosingFuncName(),
+		})
+	}
+}
+
+// shadowsOuter reports whether bound, declared by a select case, hides a
+// variable of the same name from an enclosing scope and use refers to
+// bound. Without type information for bound it assumes so.
+func (a *Analyzer) shadowsOuter(bound, use *ast.Ident) bool {
+	if a.info == nil {
+		return true
+	}
+	obj := a.info.Defs[bound]
+	if obj == nil {
+		return true
+	}
+	if a.info.Uses[use] != obj || obj.Parent() == nil || obj.Parent().Parent() == nil {
+		return false
+	}
+	scope, outer := obj.Parent().Parent().LookupParent(bound.Name, token.NoPos)
+	_, isVar := outer.(*types.Var)
+	return isVar && scope != types.Universe
+}
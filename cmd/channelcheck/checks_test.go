@@ -0,0 +1,4004 @@
+package main
+
+import (
+	"go/token"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// runAnalyzer analyzes code as a single file named test.go and returns the
+// issues found.
+func runAnalyzer(t *testing.T, code string) []Issue {
+	t.Helper()
+
+	analyzer := &Analyzer{
+		fset: token.NewFileSet(),
+	}
+	if err := analyzer.analyzeSource("test.go", []byte(code)); err != nil {
+		t.Fatalf("failed to analyze test code: %v", err)
+	}
+	return analyzer.issues
+}
+
+// runAnalyzerEnabled is runAnalyzer with the given opt-in rules enabled.
+func runAnalyzerEnabled(t *testing.T, code string, enabled ...string) []Issue {
+	t.Helper()
+
+	analyzer := &Analyzer{
+		fset:   token.NewFileSet(),
+		config: ruleConfig{enabled: make(map[string]bool)},
+	}
+	for _, rule := range enabled {
+		analyzer.config.enabled[rule] = true
+	}
+	if err := analyzer.analyzeSource("test.go", []byte(code)); err != nil {
+		t.Fatalf("failed to analyze test code: %v", err)
+	}
+	return analyzer.issues
+}
+
+// countMessages returns the number of issues whose message contains msg.
+func countMessages(issues []Issue, msg string) int {
+	count := 0
+	for _, issue := range issues {
+		if strings.Contains(issue.Message, msg) {
+			count++
+		}
+	}
+	return count
+}
+
+func TestCheckTimeAfterInLoop(t *testing.T) {
+	const msg = "time.After inside loop restarts the deadline"
+
+	tests := []struct {
+		name     string
+		code     string
+		expected int
+	}{
+		{
+			name: "per-iteration time.After in loop select",
+			code: `
+				package test
+				import "time"
+				func wait(ch chan int) {
+					for {
+						select {
+						case v := <-ch:
+							_ = v
+						case <-time.After(time.Second):
+							return
+						}
+					}
+				}
+			`,
+			expected: 1,
+		},
+		{
+			name: "time.After in range loop select",
+			code: `
+				package test
+				import "time"
+				func wait(items []int, ch chan int) {
+					for range items {
+						select {
+						case <-ch:
+						case <-time.After(time.Second):
+						}
+					}
+				}
+			`,
+			expected: 1,
+		},
+		{
+			name: "overall deadline created before the loop",
+			code: `
+				package test
+				import "time"
+				func wait(ch chan int) {
+					timeout := time.After(time.Second)
+					for {
+						select {
+						case <-ch:
+						case <-timeout:
+							return
+						}
+					}
+				}
+			`,
+			expected: 0,
+		},
+		{
+			name: "time.After outside a loop",
+			code: `
+				package test
+				import "time"
+				func wait(ch chan int) {
+					select {
+					case <-ch:
+					case <-time.After(time.Second):
+					}
+				}
+			`,
+			expected: 0,
+		},
+		{
+			name: "loop outside the goroutine running the select",
+			code: `
+				package test
+				import "time"
+				func wait(chs []chan int) {
+					for _, ch := range chs {
+						go func() {
+							select {
+							case <-ch:
+							case <-time.After(time.Second):
+							}
+						}()
+					}
+				}
+			`,
+			expected: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			issues := runAnalyzer(t, tt.code)
+			if got := countMessages(issues, msg); got != tt.expected {
+				t.Errorf("got %d issues, want %d: %s", got, tt.expected, formatIssues(issues))
+			}
+		})
+	}
+}
+
+func TestCheckSendUnderLock(t *testing.T) {
+	const msg = "channel send while holding a lock may deadlock"
+
+	tests := []struct {
+		name     string
+		code     string
+		expected int
+	}{
+		{
+			name: "send inside lock span",
+			code: `
+				package test
+				import "sync"
+				func notify(mu *sync.Mutex, ch chan int) {
+					mu.Lock()
+					ch <- 1
+					mu.Unlock()
+				}
+			`,
+			expected: 1,
+		},
+		{
+			name: "send with deferred unlock",
+			code: `
+				package test
+				import "sync"
+				type S struct {
+					mu sync.Mutex
+					ch chan int
+				}
+				func (s *S) notify() {
+					s.mu.Lock()
+					defer s.mu.Unlock()
+					if s.ch != nil {
+						s.ch <- 1
+					}
+				}
+			`,
+			expected: 1,
+		},
+		{
+			name: "send under read lock",
+			code: `
+				package test
+				import "sync"
+				func notify(mu *sync.RWMutex, ch chan int) {
+					mu.RLock()
+					ch <- 1
+					mu.RUnlock()
+				}
+			`,
+			expected: 1,
+		},
+		{
+			name: "send after unlock",
+			code: `
+				package test
+				import "sync"
+				func notify(mu *sync.Mutex, ch chan int) {
+					mu.Lock()
+					v := 1
+					mu.Unlock()
+					ch <- v
+				}
+			`,
+			expected: 0,
+		},
+		{
+			name: "send in goroutine started under lock",
+			code: `
+				package test
+				import "sync"
+				func notify(mu *sync.Mutex, ch chan int) {
+					mu.Lock()
+					defer mu.Unlock()
+					go func() {
+						ch <- 1
+					}()
+				}
+			`,
+			expected: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			issues := runAnalyzer(t, tt.code)
+			if got := countMessages(issues, msg); got != tt.expected {
+				t.Errorf("got %d issues, want %d: %s", got, tt.expected, formatIssues(issues))
+			}
+		})
+	}
+}
+
+func TestCheckSpinningDrain(t *testing.T) {
+	const msg = "draining loop spins after channel close"
+
+	tests := []struct {
+		name     string
+		code     string
+		expected int
+	}{
+		{
+			name: "bare receive in infinite loop",
+			code: `
+				package test
+				func drain(ch chan int) {
+					for {
+						<-ch
+					}
+				}
+			`,
+			expected: 1,
+		},
+		{
+			name: "range over channel",
+			code: `
+				package test
+				func drain(ch chan int) {
+					for range ch {
+					}
+				}
+			`,
+			expected: 0,
+		},
+		{
+			name: "comma-ok receive in loop",
+			code: `
+				package test
+				func drain(ch chan int) {
+					for {
+						if _, ok := <-ch; !ok {
+							return
+						}
+					}
+				}
+			`,
+			expected: 0,
+		},
+		{
+			name: "loop with condition",
+			code: `
+				package test
+				func drain(ch chan int, n int) {
+					for i := 0; i < n; i++ {
+						<-ch
+					}
+				}
+			`,
+			expected: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			issues := runAnalyzer(t, tt.code)
+			if got := countMessages(issues, msg); got != tt.expected {
+				t.Errorf("got %d issues, want %d: %s", got, tt.expected, formatIssues(issues))
+			}
+		})
+	}
+}
+
+func TestCheckBufferSize(t *testing.T) {
+	const msg = "channel buffer reserves"
+
+	tests := []struct {
+		name      string
+		code      string
+		threshold int64
+		expected  int
+	}{
+		{
+			name: "large struct with large buffer",
+			code: `
+				package test
+				type Frame struct {
+					Pixels [4096]byte
+					ID     int
+				}
+				func frames() chan Frame {
+					return make(chan Frame, 1024)
+				}
+			`,
+			expected: 1,
+		},
+		{
+			name: "small element with large buffer",
+			code: `
+				package test
+				func ints() chan int {
+					return make(chan int, 1024)
+				}
+			`,
+			expected: 0,
+		},
+		{
+			name: "pointer element",
+			code: `
+				package test
+				type Frame struct {
+					Pixels [4096]byte
+				}
+				func frames() chan *Frame {
+					return make(chan *Frame, 1024)
+				}
+			`,
+			expected: 0,
+		},
+		{
+			name: "non-literal capacity",
+			code: `
+				package test
+				type Frame struct {
+					Pixels [4096]byte
+				}
+				func frames(n int) chan Frame {
+					return make(chan Frame, n)
+				}
+			`,
+			expected: 0,
+		},
+		{
+			name: "configured threshold",
+			code: `
+				package test
+				func ints() chan int64 {
+					return make(chan int64, 16)
+				}
+			`,
+			threshold: 64,
+			expected:  1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			analyzer := &Analyzer{
+				fset:                 token.NewFileSet(),
+				bufferBytesThreshold: tt.threshold,
+			}
+			if err := analyzer.analyzeSource("test.go", []byte(tt.code)); err != nil {
+				t.Fatalf("failed to analyze test code: %v", err)
+			}
+			if got := countMessages(analyzer.issues, msg); got != tt.expected {
+				t.Errorf("got %d issues, want %d: %s", got, tt.expected, formatIssues(analyzer.issues))
+			}
+		})
+	}
+}
+
+func TestCheckIndexedSend(t *testing.T) {
+	const msg = "sending on a channel from a collection"
+
+	tests := []struct {
+		name     string
+		code     string
+		expected int
+	}{
+		{
+			name: "slice index send",
+			code: `
+				package test
+				func fanOut(chans []chan int, i int) {
+					chans[i] <- 1
+				}
+			`,
+			expected: 1,
+		},
+		{
+			name: "map index send",
+			code: `
+				package test
+				func route(m map[string]chan int, key string) {
+					m[key] <- 1
+				}
+			`,
+			expected: 1,
+		},
+		{
+			name: "direct identifier send",
+			code: `
+				package test
+				func send(ch chan int) {
+					ch <- 1
+				}
+			`,
+			expected: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			issues := runAnalyzer(t, tt.code)
+			if got := countMessages(issues, msg); got != tt.expected {
+				t.Errorf("got %d issues, want %d: %s", got, tt.expected, formatIssues(issues))
+			}
+		})
+	}
+}
+
+func TestCheckBusyRetry(t *testing.T) {
+	const msg = "busy retry of channel send without backoff"
+
+	tests := []struct {
+		name     string
+		code     string
+		expected int
+	}{
+		{
+			name: "empty default in retry loop",
+			code: `
+				package test
+				func push(ch chan int) {
+					for {
+						select {
+						case ch <- 1:
+							return
+						default:
+						}
+					}
+				}
+			`,
+			expected: 1,
+		},
+		{
+			name: "default sleeps before retrying",
+			code: `
+				package test
+				import "time"
+				func push(ch chan int) {
+					for {
+						select {
+						case ch <- 1:
+							return
+						default:
+							time.Sleep(10 * time.Millisecond)
+						}
+					}
+				}
+			`,
+			expected: 0,
+		},
+		{
+			name: "non-blocking send outside a loop",
+			code: `
+				package test
+				func push(ch chan int) {
+					select {
+					case ch <- 1:
+					default:
+					}
+				}
+			`,
+			expected: 0,
+		},
+		{
+			name: "receive with empty default in loop",
+			code: `
+				package test
+				func poll(ch chan int) {
+					for {
+						select {
+						case <-ch:
+							return
+						default:
+						}
+					}
+				}
+			`,
+			expected: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			issues := runAnalyzer(t, tt.code)
+			if got := countMessages(issues, msg); got != tt.expected {
+				t.Errorf("got %d issues, want %d: %s", got, tt.expected, formatIssues(issues))
+			}
+		})
+	}
+}
+
+func TestCheckSendAfterHelperClose(t *testing.T) {
+	const msg = "send on channel already closed by"
+
+	tests := []struct {
+		name     string
+		code     string
+		expected int
+	}{
+		{
+			name: "send after helper closes the channel",
+			code: `
+				package test
+				func finish(ch chan int) {
+					close(ch)
+				}
+				func run() {
+					ch := make(chan int, 1)
+					finish(ch)
+					ch <- 1
+				}
+			`,
+			expected: 1,
+		},
+		{
+			name: "helper closes its second parameter",
+			code: `
+				package test
+				func finish(n int, ch chan int) {
+					if n > 0 {
+						close(ch)
+					}
+				}
+				func run(ch chan int) {
+					finish(1, ch)
+					if true {
+						ch <- 1
+					}
+				}
+			`,
+			expected: 1,
+		},
+		{
+			name: "send before the helper closes",
+			code: `
+				package test
+				func finish(ch chan int) {
+					close(ch)
+				}
+				func run() {
+					ch := make(chan int, 1)
+					ch <- 1
+					finish(ch)
+				}
+			`,
+			expected: 0,
+		},
+		{
+			name: "helper does not close",
+			code: `
+				package test
+				func use(ch chan int) {
+					_ = len(ch)
+				}
+				func run(ch chan int) {
+					use(ch)
+					ch <- 1
+				}
+			`,
+			expected: 0,
+		},
+		{
+			name: "channel reassigned after close",
+			code: `
+				package test
+				func finish(ch chan int) {
+					close(ch)
+				}
+				func run() {
+					ch := make(chan int, 1)
+					finish(ch)
+					ch = make(chan int, 1)
+					ch <- 1
+				}
+			`,
+			expected: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			issues := runAnalyzer(t, tt.code)
+			if got := countMessages(issues, msg); got != tt.expected {
+				t.Errorf("got %d issues, want %d: %s", got, tt.expected, formatIssues(issues))
+			}
+		})
+	}
+}
+
+func TestCheckReceiveInLoopCond(t *testing.T) {
+	const msg = "channel receive in for-loop condition"
+
+	tests := []struct {
+		name     string
+		code     string
+		expected int
+	}{
+		{
+			name: "receive as condition",
+			code: `
+				package test
+				func loop(ready chan bool) {
+					for <-ready {
+					}
+				}
+			`,
+			expected: 1,
+		},
+		{
+			name: "receive inside a comparison",
+			code: `
+				package test
+				func loop(n chan int) {
+					for i := 0; i < <-n; i++ {
+					}
+				}
+			`,
+			expected: 1,
+		},
+		{
+			name: "ordinary condition",
+			code: `
+				package test
+				func loop(n int) {
+					for i := 0; i < n; i++ {
+					}
+				}
+			`,
+			expected: 0,
+		},
+		{
+			name: "range over channel",
+			code: `
+				package test
+				func loop(ch chan int) {
+					for v := range ch {
+						_ = v
+					}
+				}
+			`,
+			expected: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			issues := runAnalyzer(t, tt.code)
+			if got := countMessages(issues, msg); got != tt.expected {
+				t.Errorf("got %d issues, want %d: %s", got, tt.expected, formatIssues(issues))
+			}
+		})
+	}
+}
+
+func TestCheckSingleCaseSelect(t *testing.T) {
+	const msg = "single-case select without default"
+
+	tests := []struct {
+		name     string
+		code     string
+		expected int
+	}{
+		{
+			name: "single case without default",
+			code: `
+				package test
+				func send(ch chan int) {
+					select {
+					case ch <- 1:
+					}
+				}
+			`,
+			expected: 1,
+		},
+		{
+			name: "single case with default",
+			code: `
+				package test
+				func send(ch chan int) {
+					select {
+					case ch <- 1:
+					default:
+					}
+				}
+			`,
+			expected: 0,
+		},
+		{
+			name: "multiple cases",
+			code: `
+				package test
+				func send(ch chan int, done chan struct{}) {
+					select {
+					case ch <- 1:
+					case <-done:
+					}
+				}
+			`,
+			expected: 0,
+		},
+		{
+			name: "empty select",
+			code: `
+				package test
+				func block() {
+					select {}
+				}
+			`,
+			expected: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			issues := runAnalyzer(t, tt.code)
+			if got := countMessages(issues, msg); got != tt.expected {
+				t.Errorf("got %d issues, want %d: %s", got, tt.expected, formatIssues(issues))
+			}
+		})
+	}
+}
+
+func TestCheckSingleCaseSelect_Receive(t *testing.T) {
+	const msg = "single-case select on a receive is just a blocking receive — use <-ch directly, or add a timeout case"
+
+	tests := []struct {
+		name     string
+		code     string
+		expected int
+	}{
+		{
+			name: "single receive case",
+			code: `
+				package test
+				func wait(ch chan int) int {
+					select {
+					case v := <-ch:
+						return v
+					}
+				}
+			`,
+			expected: 1,
+		},
+		{
+			name: "receive with a timeout",
+			code: `
+				package test
+				import "time"
+				func wait(ch chan int, d time.Duration) int {
+					select {
+					case v := <-ch:
+						return v
+					case <-time.After(d):
+						return 0
+					}
+				}
+			`,
+			expected: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			issues := runAnalyzer(t, tt.code)
+			if got := countMessages(issues, msg); got != tt.expected {
+				t.Errorf("got %d issues, want %d: %s", got, tt.expected, formatIssues(issues))
+			}
+		})
+	}
+}
+
+func TestCheckConditionalMake(t *testing.T) {
+	const msg = "channel may be nil on some paths"
+
+	tests := []struct {
+		name     string
+		code     string
+		expected int
+	}{
+		{
+			name: "make inside if, send afterwards",
+			code: `
+				package test
+				func run(enabled bool) {
+					var ch chan int
+					if enabled {
+						ch = make(chan int, 1)
+					}
+					ch <- 1
+				}
+			`,
+			expected: 1,
+		},
+		{
+			name: "make in one switch case",
+			code: `
+				package test
+				func run(mode int) {
+					var ch chan int
+					switch mode {
+					case 1:
+						ch = make(chan int, 1)
+					}
+					ch <- 1
+				}
+			`,
+			expected: 1,
+		},
+		{
+			name: "unconditional make",
+			code: `
+				package test
+				func run() {
+					ch := make(chan int, 1)
+					ch <- 1
+				}
+			`,
+			expected: 0,
+		},
+		{
+			name: "made on both paths",
+			code: `
+				package test
+				func run(enabled bool) {
+					var ch chan int
+					if enabled {
+						ch = make(chan int, 1)
+					}
+					ch = make(chan int, 2)
+					ch <- 1
+				}
+			`,
+			expected: 0,
+		},
+		{
+			name: "send inside the same branch",
+			code: `
+				package test
+				func run(enabled bool) {
+					var ch chan int
+					if enabled {
+						ch = make(chan int, 1)
+						ch <- 1
+					}
+				}
+			`,
+			expected: 0,
+		},
+		{
+			name: "parameter channel",
+			code: `
+				package test
+				func run(ch chan int) {
+					ch <- 1
+				}
+			`,
+			expected: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			issues := runAnalyzer(t, tt.code)
+			if got := countMessages(issues, msg); got != tt.expected {
+				t.Errorf("got %d issues, want %d: %s", got, tt.expected, formatIssues(issues))
+			}
+		})
+	}
+}
+
+func TestCheckProducerClose(t *testing.T) {
+	const msg = "multiple goroutines send and one closes"
+
+	tests := []struct {
+		name     string
+		code     string
+		expected int
+	}{
+		{
+			name: "one of several producers closes",
+			code: `
+				package test
+				func produce(ch chan int) {
+					go func() {
+						ch <- 1
+						close(ch)
+					}()
+					go func() {
+						ch <- 2
+					}()
+				}
+			`,
+			expected: 1,
+		},
+		{
+			name: "producers in a loop with a separate closer",
+			code: `
+				package test
+				func produce(ch chan int, done chan struct{}) {
+					go func() {
+						ch <- 1
+					}()
+					go func() {
+						ch <- 2
+					}()
+					go func() {
+						<-done
+						close(ch)
+					}()
+				}
+			`,
+			expected: 1,
+		},
+		{
+			name: "single producer closes",
+			code: `
+				package test
+				func produce(ch chan int, other chan int) {
+					go func() {
+						ch <- 1
+						close(ch)
+					}()
+					go func() {
+						other <- 2
+					}()
+				}
+			`,
+			expected: 0,
+		},
+		{
+			name: "producers in different functions",
+			code: `
+				package test
+				func a(ch chan int) {
+					go func() {
+						ch <- 1
+						close(ch)
+					}()
+				}
+				func b(ch chan int) {
+					go func() {
+						ch <- 2
+					}()
+				}
+			`,
+			expected: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			issues := runAnalyzer(t, tt.code)
+			if got := countMessages(issues, msg); got != tt.expected {
+				t.Errorf("got %d issues, want %d: %s", got, tt.expected, formatIssues(issues))
+			}
+		})
+	}
+}
+
+func TestCheckSilentDrop(t *testing.T) {
+	const msg = "non-blocking send on unbuffered channel may silently drop"
+
+	tests := []struct {
+		name     string
+		code     string
+		expected int
+	}{
+		{
+			name: "empty default on unbuffered channel",
+			code: `
+				package test
+				func notify() {
+					ch := make(chan int)
+					select {
+					case ch <- 1:
+					default:
+					}
+				}
+			`,
+			expected: 1,
+		},
+		{
+			name: "default handles the drop",
+			code: `
+				package test
+				import "log"
+				func notify() {
+					ch := make(chan int)
+					select {
+					case ch <- 1:
+					default:
+						log.Println("dropped notification")
+					}
+				}
+			`,
+			expected: 0,
+		},
+		{
+			name: "buffered channel",
+			code: `
+				package test
+				func notify() {
+					ch := make(chan int, 8)
+					select {
+					case ch <- 1:
+					default:
+					}
+				}
+			`,
+			expected: 0,
+		},
+		{
+			name: "channel of unknown buffering",
+			code: `
+				package test
+				func notify(ch chan int) {
+					select {
+					case ch <- 1:
+					default:
+					}
+				}
+			`,
+			expected: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			issues := runAnalyzer(t, tt.code)
+			if got := countMessages(issues, msg); got != tt.expected {
+				t.Errorf("got %d issues, want %d: %s", got, tt.expected, formatIssues(issues))
+			}
+		})
+	}
+}
+
+func TestCheckChannelSend_Field(t *testing.T) {
+	issues := runAnalyzer(t, `
+		package test
+		type Server struct {
+			inner struct{ events chan int }
+			events chan int
+		}
+		func (s *Server) publish(e int) {
+			s.events <- e
+			s.inner.events <- e
+		}
+		func publish(ch chan int, e int) {
+			ch <- e
+		}
+	`)
+
+	want := []struct{ message, fn string }{
+		{"channel send without select statement may block indefinitely (field s.events)", "Server.publish"},
+		{"channel send without select statement may block indefinitely (field s.inner.events)", "Server.publish"},
+		{"channel send without select statement may block indefinitely", "publish"},
+	}
+	var got []Issue
+	for _, issue := range issues {
+		if issue.Rule == RuleSendWithoutSelect {
+			got = append(got, issue)
+		}
+	}
+	if len(got) != len(want) {
+		t.Fatalf("got %d issues, want %d: %s", len(got), len(want), formatIssues(got))
+	}
+	for i, w := range want {
+		if got[i].Message != w.message || got[i].Func != w.fn {
+			t.Errorf("issue %d: got %q in %q, want %q in %q", i, got[i].Message, got[i].Func, w.message, w.fn)
+		}
+	}
+}
+
+func TestCheckChannelSend_SafeFuncs(t *testing.T) {
+	const code = `package test
+type Bus struct{ events chan int }
+func safeSend(ch chan int, v int) {
+	ch <- v
+}
+func (b *Bus) publish(v int) {
+	b.events <- v
+}
+func unsafeSend(ch chan int, v int) {
+	ch <- v
+}
+`
+	analyzer := &Analyzer{fset: token.NewFileSet(), safeFuncs: []string{"safeSend", "Bus.publish"}}
+	if err := analyzer.analyzeSource("test.go", []byte(code)); err != nil {
+		t.Fatalf("analysis failed: %v", err)
+	}
+
+	var funcs []string
+	for _, issue := range analyzer.issues {
+		if issue.Rule == RuleSendWithoutSelect {
+			funcs = append(funcs, issue.Func)
+		}
+	}
+	if !slices.Equal(funcs, []string{"unsafeSend"}) {
+		t.Errorf("got sends reported in %v, want only unsafeSend", funcs)
+	}
+}
+
+func TestCheckInvertedCommaOk(t *testing.T) {
+	const msg = "using value received from a closed channel"
+
+	tests := []struct {
+		name     string
+		code     string
+		expected int
+	}{
+		{
+			name: "value used when not ok",
+			code: `
+				package test
+				func consume(ch chan int, handle func(int)) {
+					if v, ok := <-ch; !ok {
+						handle(v)
+					}
+				}
+			`,
+			expected: 1,
+		},
+		{
+			name: "ok compared to false",
+			code: `
+				package test
+				func consume(ch chan int) int {
+					if v, ok := <-ch; ok == false {
+						return v
+					}
+					return 0
+				}
+			`,
+			expected: 1,
+		},
+		{
+			name: "value used when ok",
+			code: `
+				package test
+				func consume(ch chan int, handle func(int)) {
+					if v, ok := <-ch; ok {
+						handle(v)
+					}
+				}
+			`,
+			expected: 0,
+		},
+		{
+			name: "closed branch does not read the value",
+			code: `
+				package test
+				func consume(ch chan int) int {
+					if v, ok := <-ch; !ok {
+						return -1
+					} else {
+						return v
+					}
+				}
+			`,
+			expected: 0,
+		},
+		{
+			name: "shadowed name in the branch",
+			code: `
+				package test
+				func consume(ch chan int, handle func(int)) {
+					if v, ok := <-ch; !ok {
+						v := 0
+						handle(v)
+					}
+				}
+			`,
+			expected: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			issues := runAnalyzer(t, tt.code)
+			if got := countMessages(issues, msg); got != tt.expected {
+				t.Errorf("got %d issues, want %d: %s", got, tt.expected, formatIssues(issues))
+			}
+		})
+	}
+}
+
+func TestCheckUnreachableChannel(t *testing.T) {
+	const msg = "channel used only in unreachable code"
+
+	tests := []struct {
+		name     string
+		code     string
+		expected int
+	}{
+		{
+			name: "select inside if false",
+			code: `
+				package test
+				func wait() {
+					done := make(chan struct{}, 1)
+					if false {
+						select {
+						case <-done:
+						default:
+						}
+					}
+				}
+			`,
+			expected: 1,
+		},
+		{
+			name: "also used in reachable code",
+			code: `
+				package test
+				func wait() {
+					done := make(chan struct{}, 1)
+					done <- struct{}{}
+					if false {
+						select {
+						case <-done:
+						default:
+						}
+					}
+				}
+			`,
+			expected: 0,
+		},
+		{
+			name: "select under a real condition",
+			code: `
+				package test
+				func wait(debug bool) {
+					done := make(chan struct{}, 1)
+					if debug {
+						select {
+						case <-done:
+						default:
+						}
+					}
+				}
+			`,
+			expected: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			issues := runAnalyzer(t, tt.code)
+			if got := countMessages(issues, msg); got != tt.expected {
+				t.Errorf("got %d issues, want %d: %s", got, tt.expected, formatIssues(issues))
+			}
+		})
+	}
+}
+
+func TestCheckSendInRecover(t *testing.T) {
+	const msg = "channel send inside panic recovery may block cleanup"
+
+	tests := []struct {
+		name     string
+		code     string
+		expected int
+	}{
+		{
+			name: "recover then send",
+			code: `
+				package test
+				import "fmt"
+				func work(errCh chan error) {
+					defer func() {
+						if r := recover(); r != nil {
+							errCh <- fmt.Errorf("panic: %v", r)
+						}
+					}()
+				}
+			`,
+			expected: 1,
+		},
+		{
+			name: "select-guarded send",
+			code: `
+				package test
+				import "fmt"
+				func work(errCh chan error) {
+					defer func() {
+						if r := recover(); r != nil {
+							select {
+							case errCh <- fmt.Errorf("panic: %v", r):
+							default:
+							}
+						}
+					}()
+				}
+			`,
+			expected: 0,
+		},
+		{
+			name: "deferred send without recover",
+			code: `
+				package test
+				func work(done chan bool) {
+					defer func() {
+						done <- true
+					}()
+				}
+			`,
+			expected: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			issues := runAnalyzer(t, tt.code)
+			if got := countMessages(issues, msg); got != tt.expected {
+				t.Errorf("got %d issues, want %d: %s", got, tt.expected, formatIssues(issues))
+			}
+		})
+	}
+}
+
+func TestCheckLogOnlyDefault(t *testing.T) {
+	const msg = "dropped channel work is only logged, not measured"
+
+	tests := []struct {
+		name     string
+		code     string
+		logFuncs []string
+		expected int
+	}{
+		{
+			name: "log-only default",
+			code: `
+				package test
+				import "log"
+				func submit(jobs chan int, job int) {
+					select {
+					case jobs <- job:
+					default:
+						log.Printf("dropped job %d", job)
+					}
+				}
+			`,
+			expected: 1,
+		},
+		{
+			name: "default increments a metric",
+			code: `
+				package test
+				import "log"
+				var dropped int
+				func submit(jobs chan int, job int) {
+					select {
+					case jobs <- job:
+					default:
+						dropped++
+						log.Printf("dropped job %d", job)
+					}
+				}
+			`,
+			expected: 0,
+		},
+		{
+			name: "custom log function",
+			code: `
+				package test
+				type Logger struct{}
+				func (Logger) Warn(msg string) {}
+				var logger Logger
+				func submit(jobs chan int, job int) {
+					select {
+					case jobs <- job:
+					default:
+						logger.Warn("dropped job")
+					}
+				}
+			`,
+			logFuncs: []string{"logger.*"},
+			expected: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			analyzer := &Analyzer{
+				fset:     token.NewFileSet(),
+				logFuncs: tt.logFuncs,
+				config:   ruleConfig{enabled: map[string]bool{RuleLogOnlyDefault: true}},
+			}
+			if err := analyzer.analyzeSource("test.go", []byte(tt.code)); err != nil {
+				t.Fatalf("failed to analyze test code: %v", err)
+			}
+			if got := countMessages(analyzer.issues, msg); got != tt.expected {
+				t.Errorf("got %d issues, want %d: %s", got, tt.expected, formatIssues(analyzer.issues))
+			}
+		})
+	}
+
+	// The rule is opt-in.
+	issues := runAnalyzer(t, tests[0].code)
+	if got := countMessages(issues, msg); got != 0 {
+		t.Errorf("opt-in rule reported without being enabled: %s", formatIssues(issues))
+	}
+}
+
+func TestCheckRangeSignal(t *testing.T) {
+	const msg = "ranging a close-signal channel yields nothing; use a single <-done"
+
+	tests := []struct {
+		name     string
+		code     string
+		expected int
+	}{
+		{
+			name: "range over closed signal channel",
+			code: `
+				package test
+				func wait(work func()) {
+					done := make(chan struct{})
+					go func() {
+						work()
+						close(done)
+					}()
+					for range done {
+					}
+				}
+			`,
+			expected: 1,
+		},
+		{
+			name: "single receive",
+			code: `
+				package test
+				func wait(work func()) {
+					done := make(chan struct{})
+					go func() {
+						work()
+						close(done)
+					}()
+					<-done
+				}
+			`,
+			expected: 0,
+		},
+		{
+			name: "signal channel that is also sent to",
+			code: `
+				package test
+				func wait(work func()) {
+					done := make(chan struct{})
+					go func() {
+						work()
+						done <- struct{}{}
+						close(done)
+					}()
+					for range done {
+					}
+				}
+			`,
+			expected: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			issues := runAnalyzer(t, tt.code)
+			if got := countMessages(issues, msg); got != tt.expected {
+				t.Errorf("got %d issues, want %d: %s", got, tt.expected, formatIssues(issues))
+			}
+		})
+	}
+}
+
+func TestCheckReturnedUnbuffered(t *testing.T) {
+	const msg = "exported function returns an unbuffered channel"
+
+	tests := []struct {
+		name     string
+		code     string
+		expected int
+	}{
+		{
+			name: "returned unbuffered variable",
+			code: `
+				package test
+				func Events() <-chan int {
+					ch := make(chan int)
+					go func() { ch <- 1 }()
+					return ch
+				}
+			`,
+			expected: 1,
+		},
+		{
+			name: "returned unbuffered make",
+			code: `
+				package test
+				func Events() chan int {
+					return make(chan int)
+				}
+			`,
+			expected: 1,
+		},
+		{
+			name: "buffered return",
+			code: `
+				package test
+				func Events() <-chan int {
+					ch := make(chan int, 16)
+					return ch
+				}
+			`,
+			expected: 0,
+		},
+		{
+			name: "documented buffering",
+			code: `
+				package test
+				// Events returns an unbuffered channel; receive promptly.
+				func Events() chan int {
+					return make(chan int)
+				}
+			`,
+			expected: 0,
+		},
+		{
+			name: "unexported function",
+			code: `
+				package test
+				func events() chan int {
+					return make(chan int)
+				}
+			`,
+			expected: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			issues := runAnalyzerEnabled(t, tt.code, RuleReturnedUnbuffered)
+			if got := countMessages(issues, msg); got != tt.expected {
+				t.Errorf("got %d issues, want %d: %s", got, tt.expected, formatIssues(issues))
+			}
+		})
+	}
+}
+
+func TestGenerics(t *testing.T) {
+	issues := runAnalyzer(t, `
+		package test
+
+		func Send[T any](ch chan T, v T) {
+			ch <- v
+		}
+
+		type Pipe[K comparable, V any] struct {
+			out chan map[K]V
+		}
+
+		func (p *Pipe[K, V]) Emit(m map[K]V) {
+			p.out <- m
+		}
+
+		func NewPipe[K comparable, V any]() *Pipe[K, V] {
+			return &Pipe[K, V]{out: make(chan map[K]V)}
+		}
+
+		func use() {
+			ch := make(chan int, 1)
+			Send[int](ch, 1)
+			Send(ch, 2)
+			NewPipe[string, int]().Emit(nil)
+		}
+	`)
+
+	want := []struct{ message, fn string }{
+		{"channel send without select statement may block indefinitely", "Send"},
+		{"channel send without select statement may block indefinitely (field p.out)", "Pipe.Emit"},
+	}
+	var got []Issue
+	for _, issue := range issues {
+		if issue.Rule == RuleSendWithoutSelect {
+			got = append(got, issue)
+		}
+	}
+	if len(got) != len(want) {
+		t.Fatalf("got %d sends, want %d: %s", len(got), len(want), formatIssues(got))
+	}
+	for i, w := range want {
+		if got[i].Message != w.message || got[i].Func != w.fn {
+			t.Errorf("issue %d: got %q in %q, want %q in %q", i, got[i].Message, got[i].Func, w.message, w.fn)
+		}
+	}
+
+	if got := countMessages(issues, "unbuffered channel creation"); got != 1 {
+		t.Errorf("got %d unbuffered creations, want the one in NewPipe: %s", got, formatIssues(issues))
+	}
+}
+
+func TestCheckUncoordinatedFanIn(t *testing.T) {
+	const msg = "fan-in with multiple channels lacks close coordination"
+
+	tests := []struct {
+		name     string
+		code     string
+		expected int
+	}{
+		{
+			name: "uncoordinated fan-in",
+			code: `
+				package test
+				func merge(a, b func() int) []int {
+					left := make(chan int)
+					right := make(chan int)
+					go func() { left <- a() }()
+					go func() { right <- b() }()
+					return []int{<-left, <-right}
+				}
+			`,
+			expected: 1,
+		},
+		{
+			name: "producers close their channels",
+			code: `
+				package test
+				func merge(a, b func() int) []int {
+					left := make(chan int)
+					right := make(chan int)
+					go func() {
+						defer close(left)
+						left <- a()
+					}()
+					go func() {
+						defer close(right)
+						right <- b()
+					}()
+					var out []int
+					for v := range left {
+						out = append(out, v)
+					}
+					for v := range right {
+						out = append(out, v)
+					}
+					return out
+				}
+			`,
+			expected: 0,
+		},
+		{
+			name: "single channel",
+			code: `
+				package test
+				func result(a func() int) int {
+					ch := make(chan int)
+					go func() { ch <- a() }()
+					return <-ch
+				}
+			`,
+			expected: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			issues := runAnalyzer(t, tt.code)
+			if got := countMessages(issues, msg); got != tt.expected {
+				t.Errorf("got %d issues, want %d: %s", got, tt.expected, formatIssues(issues))
+			}
+		})
+	}
+}
+
+func TestCheckUnusedReceive(t *testing.T) {
+	const msg = "received value is never used"
+
+	tests := []struct {
+		name     string
+		code     string
+		expected int
+	}{
+		{
+			name: "second value never read",
+			code: `
+				package test
+				func drain(ch chan int, log func(int)) {
+					v := <-ch
+					log(v)
+					v = <-ch
+				}
+			`,
+			expected: 1,
+		},
+		{
+			name: "value used",
+			code: `
+				package test
+				func wait(ch chan int) int {
+					v := <-ch
+					return v
+				}
+			`,
+			expected: 0,
+		},
+		{
+			name: "value read on the next iteration",
+			code: `
+				package test
+				func sum(ch chan int) int {
+					total, v := 0, 0
+					for i := 0; i < 3; i++ {
+						total += v
+						v = <-ch
+					}
+					return total
+				}
+			`,
+			expected: 0,
+		},
+		{
+			name: "value read by a deferred closure",
+			code: `
+				package test
+				func wait(ch chan int, report func(int)) {
+					var v int
+					defer func() { report(v) }()
+					v = <-ch
+				}
+			`,
+			expected: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			issues := runAnalyzer(t, tt.code)
+			if got := countMessages(issues, msg); got != tt.expected {
+				t.Errorf("got %d issues, want %d: %s", got, tt.expected, formatIssues(issues))
+			}
+		})
+	}
+}
+
+func TestCheckCapturedReassignedChan(t *testing.T) {
+	const msg = "goroutine captures channel variable ch that the loop reassigns"
+
+	tests := []struct {
+		name     string
+		code     string
+		expected int
+	}{
+		{
+			name: "captured and reassigned",
+			code: `
+				package test
+				func start(next func() chan int) {
+					var ch chan int
+					for i := 0; i < 3; i++ {
+						ch = next()
+						go func() {
+							ch <- i
+						}()
+					}
+				}
+			`,
+			expected: 1,
+		},
+		{
+			name: "passed as an argument",
+			code: `
+				package test
+				func start(next func() chan int) {
+					var ch chan int
+					for i := 0; i < 3; i++ {
+						ch = next()
+						go func(ch chan int) {
+							ch <- i
+						}(ch)
+					}
+				}
+			`,
+			expected: 0,
+		},
+		{
+			name: "declared per iteration",
+			code: `
+				package test
+				func start(next func() chan int) {
+					for i := 0; i < 3; i++ {
+						ch := next()
+						go func() {
+							ch <- i
+						}()
+					}
+				}
+			`,
+			expected: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			issues := runAnalyzer(t, tt.code)
+			if got := countMessages(issues, msg); got != tt.expected {
+				t.Errorf("got %d issues, want %d: %s", got, tt.expected, formatIssues(issues))
+			}
+		})
+	}
+}
+
+func TestCheckNilCheckFallthrough(t *testing.T) {
+	const msg = "send after `if ch == nil` falls through"
+
+	tests := []struct {
+		name     string
+		code     string
+		expected int
+	}{
+		{
+			name: "nil check that only logs",
+			code: `
+				package test
+				import "log"
+				func publish(ch chan int, v int) {
+					if ch == nil {
+						log.Println("no subscriber")
+					}
+					ch <- v
+				}
+			`,
+			expected: 1,
+		},
+		{
+			name: "empty nil check",
+			code: `
+				package test
+				func publish(ch chan int, v int) {
+					if nil == ch {
+					}
+					ch <- v
+				}
+			`,
+			expected: 1,
+		},
+		{
+			name: "nil check returns",
+			code: `
+				package test
+				func publish(ch chan int, v int) {
+					if ch == nil {
+						return
+					}
+					ch <- v
+				}
+			`,
+			expected: 0,
+		},
+		{
+			name: "guarded send",
+			code: `
+				package test
+				func publish(ch chan int, v int) {
+					if ch != nil {
+						ch <- v
+					}
+				}
+			`,
+			expected: 0,
+		},
+		{
+			name: "lazy initialization",
+			code: `
+				package test
+				func publish(ch chan int, v int) {
+					if ch == nil {
+						ch = make(chan int, 1)
+					}
+					ch <- v
+				}
+			`,
+			expected: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			issues := runAnalyzer(t, tt.code)
+			if got := countMessages(issues, msg); got != tt.expected {
+				t.Errorf("got %d issues, want %d: %s", got, tt.expected, formatIssues(issues))
+			}
+		})
+	}
+}
+
+func TestCheckUnusedChanParam(t *testing.T) {
+	const msg = "function receives a channel parameter it never uses"
+
+	tests := []struct {
+		name     string
+		code     string
+		expected int
+	}{
+		{
+			name: "unused channel parameter",
+			code: `
+				package test
+				func worker(jobs chan int, results chan int) {
+					for job := range jobs {
+						_ = job * 2
+					}
+				}
+				func start() {
+					jobs := make(chan int, 1)
+					results := make(chan int, 1)
+					go worker(jobs, results)
+				}
+			`,
+			expected: 1,
+		},
+		{
+			name: "used channel parameters",
+			code: `
+				package test
+				func worker(jobs <-chan int, results chan<- int) {
+					for job := range jobs {
+						results <- job * 2
+					}
+				}
+				func start() {
+					jobs := make(chan int, 1)
+					results := make(chan int, 1)
+					go worker(jobs, results)
+				}
+			`,
+			expected: 0,
+		},
+		{
+			name: "not started as a goroutine",
+			code: `
+				package test
+				func handler(done chan struct{}) {}
+				func start() {
+					handler(nil)
+				}
+			`,
+			expected: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			issues := runAnalyzer(t, tt.code)
+			if got := countMessages(issues, msg); got != tt.expected {
+				t.Errorf("got %d issues, want %d: %s", got, tt.expected, formatIssues(issues))
+			}
+		})
+	}
+}
+
+func TestCheckSiblingClose(t *testing.T) {
+	const msg = "select may send on a channel that a sibling case closes"
+
+	tests := []struct {
+		name     string
+		code     string
+		expected int
+	}{
+		{
+			name: "sibling case closes the send channel",
+			code: `
+				package test
+				func produce(out chan int, done chan struct{}) {
+					for i := 0; ; i++ {
+						select {
+						case out <- i:
+						case <-done:
+							close(out)
+							return
+						}
+					}
+				}
+			`,
+			expected: 1,
+		},
+		{
+			name: "sibling case closes a field channel",
+			code: `
+				package test
+				type pipe struct{ out chan int }
+				func (p *pipe) run(v int, done chan struct{}) {
+					select {
+					case p.out <- v:
+					case <-done:
+						close(p.out)
+					}
+				}
+			`,
+			expected: 1,
+		},
+		{
+			name: "close after the select",
+			code: `
+				package test
+				func produce(out chan int, done chan struct{}) {
+					defer close(out)
+					for i := 0; ; i++ {
+						select {
+						case out <- i:
+						case <-done:
+							return
+						}
+					}
+				}
+			`,
+			expected: 0,
+		},
+		{
+			name: "sibling case closes another channel",
+			code: `
+				package test
+				func produce(out chan int, done chan struct{}, stopped chan struct{}) {
+					select {
+					case out <- 1:
+					case <-done:
+						close(stopped)
+					}
+				}
+			`,
+			expected: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			issues := runAnalyzer(t, tt.code)
+			if got := countMessages(issues, msg); got != tt.expected {
+				t.Errorf("got %d issues, want %d: %s", got, tt.expected, formatIssues(issues))
+			}
+		})
+	}
+}
+
+func TestCheckReaderLeak(t *testing.T) {
+	const msg = "spawned reader goroutine has no cancellation path"
+
+	tests := []struct {
+		name     string
+		code     string
+		expected int
+	}{
+		{
+			name: "reader loop without cancellation",
+			code: `
+				package test
+				func consume(ch chan int) {
+					go func() {
+						for {
+							handle(<-ch)
+						}
+					}()
+				}
+				func handle(int) {}
+			`,
+			expected: 1,
+		},
+		{
+			name: "single receive without cancellation",
+			code: `
+				package test
+				func await(ch chan error) {
+					go func() {
+						err := <-ch
+						_ = err
+					}()
+				}
+			`,
+			expected: 1,
+		},
+		{
+			name: "for-select reader with a done case",
+			code: `
+				package test
+				func consume(ch chan int, done chan struct{}) {
+					go func() {
+						for {
+							select {
+							case v := <-ch:
+								_ = v
+							case <-done:
+								return
+							}
+						}
+					}()
+				}
+			`,
+			expected: 0,
+		},
+		{
+			name: "channel closed by the spawner",
+			code: `
+				package test
+				func consume() {
+					ch := make(chan int, 1)
+					go func() {
+						<-ch
+					}()
+					ch <- 1
+					close(ch)
+				}
+			`,
+			expected: 0,
+		},
+		{
+			name: "waits on context",
+			code: `
+				package test
+				import "context"
+				func watch(ctx context.Context, ch chan int) {
+					go func() {
+						<-ctx.Done()
+					}()
+				}
+			`,
+			expected: 0,
+		},
+		{
+			name: "reader loop on a struct field",
+			code: `
+				package test
+				type server struct{ events chan int }
+				func (s *server) consume() {
+					go func() {
+						for {
+							<-s.events
+						}
+					}()
+				}
+			`,
+			expected: 1,
+		},
+		{
+			name: "field receive does not hide a bare receive",
+			code: `
+				package test
+				type server struct{ events chan int }
+				func (s *server) consume(ch chan int) {
+					go func() {
+						for {
+							<-s.events
+							<-ch
+						}
+					}()
+				}
+			`,
+			expected: 1,
+		},
+		{
+			name: "indexed receive without cancellation",
+			code: `
+				package test
+				func consume(chans []chan int, i int) {
+					go func() {
+						<-chans[i]
+					}()
+				}
+			`,
+			expected: 1,
+		},
+		{
+			name: "struct field closed by the spawner",
+			code: `
+				package test
+				type server struct{ events chan int }
+				func (s *server) consume() {
+					go func() {
+						<-s.events
+					}()
+					close(s.events)
+				}
+			`,
+			expected: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			issues := runAnalyzer(t, tt.code)
+			if got := countMessages(issues, msg); got != tt.expected {
+				t.Errorf("got %d issues, want %d: %s", got, tt.expected, formatIssues(issues))
+			}
+		})
+	}
+}
+
+func TestCheckFuncChan(t *testing.T) {
+	const msg = "channel of functions — ensure handlers don't block the channel's goroutine"
+
+	tests := []struct {
+		name     string
+		code     string
+		expected int
+	}{
+		{
+			name: "channel of functions",
+			code: `
+				package test
+				func loop() {
+					tasks := make(chan func(), 8)
+					for task := range tasks {
+						task()
+					}
+				}
+			`,
+			expected: 1,
+		},
+		{
+			name: "channel of ints",
+			code: `
+				package test
+				func loop() {
+					values := make(chan int, 8)
+					for v := range values {
+						_ = v
+					}
+				}
+			`,
+			expected: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			issues := runAnalyzerEnabled(t, tt.code, RuleFuncChan)
+			if got := countMessages(issues, msg); got != tt.expected {
+				t.Errorf("got %d issues, want %d: %s", got, tt.expected, formatIssues(issues))
+			}
+		})
+	}
+
+	if issues := runAnalyzer(t, tests[0].code); countMessages(issues, msg) != 0 {
+		t.Errorf("func-chan reported without being enabled: %s", formatIssues(issues))
+	}
+}
+
+func TestCheckNilReceiverSend(t *testing.T) {
+	const msg = "send on field s.ch of pointer receiver s without a nil check — a nil receiver panics"
+
+	tests := []struct {
+		name     string
+		code     string
+		expected int
+	}{
+		{
+			name: "unguarded method send",
+			code: `
+				package test
+				type Server struct{ ch chan int }
+				func (s *Server) notify(x int) {
+					s.ch <- x
+				}
+			`,
+			expected: 1,
+		},
+		{
+			name: "guarded by an early return",
+			code: `
+				package test
+				type Server struct{ ch chan int }
+				func (s *Server) notify(x int) {
+					if s == nil {
+						return
+					}
+					s.ch <- x
+				}
+			`,
+			expected: 0,
+		},
+		{
+			name: "guarded by a non-nil branch",
+			code: `
+				package test
+				type Server struct{ ch chan int }
+				func (s *Server) notify(x int) {
+					if s != nil {
+						s.ch <- x
+					}
+				}
+			`,
+			expected: 0,
+		},
+		{
+			name: "value receiver",
+			code: `
+				package test
+				type Server struct{ ch chan int }
+				func (s Server) notify(x int) {
+					s.ch <- x
+				}
+			`,
+			expected: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			issues := runAnalyzer(t, tt.code)
+			if got := countMessages(issues, msg); got != tt.expected {
+				t.Errorf("got %d issues, want %d: %s", got, tt.expected, formatIssues(issues))
+			}
+		})
+	}
+}
+
+func TestCheckLenBuffer(t *testing.T) {
+	const msg = "buffering the entire input defeats backpressure — consider a bounded pool"
+
+	tests := []struct {
+		name     string
+		code     string
+		expected int
+	}{
+		{
+			name: "buffer sized to the input",
+			code: `
+				package test
+				func enqueue(items []string) chan string {
+					ch := make(chan string, len(items))
+					for _, it := range items {
+						ch <- it
+					}
+					close(ch)
+					return ch
+				}
+			`,
+			expected: 1,
+		},
+		{
+			name: "bounded pool with a small buffer",
+			code: `
+				package test
+				import "sync"
+				func process(items []string, work func(string)) {
+					ch := make(chan string, 4)
+					var wg sync.WaitGroup
+					for range 4 {
+						wg.Add(1)
+						go func() {
+							defer wg.Done()
+							for it := range ch {
+								work(it)
+							}
+						}()
+					}
+					for _, it := range items {
+						ch <- it
+					}
+					close(ch)
+					wg.Wait()
+				}
+			`,
+			expected: 0,
+		},
+		{
+			name: "sized by one slice, ranging over another",
+			code: `
+				package test
+				func enqueue(items, others []string) {
+					ch := make(chan string, len(items))
+					for _, it := range others {
+						ch <- it
+					}
+				}
+			`,
+			expected: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			issues := runAnalyzerEnabled(t, tt.code, RuleLenBuffer)
+			if got := countMessages(issues, msg); got != tt.expected {
+				t.Errorf("got %d issues, want %d: %s", got, tt.expected, formatIssues(issues))
+			}
+		})
+	}
+}
+
+func TestCheckContextValue(t *testing.T) {
+	const msg = "storing a channel in context is an anti-pattern"
+
+	tests := []struct {
+		name     string
+		code     string
+		expected int
+	}{
+		{
+			name: "channel stored in context",
+			code: `
+				package test
+				import "context"
+				type resultsKey struct{}
+				func withResults(ctx context.Context) context.Context {
+					results := make(chan int, 1)
+					return context.WithValue(ctx, resultsKey{}, results)
+				}
+			`,
+			expected: 1,
+		},
+		{
+			name: "plain value stored in context",
+			code: `
+				package test
+				import "context"
+				type userKey struct{}
+				func withUser(ctx context.Context, user string) context.Context {
+					return context.WithValue(ctx, userKey{}, user)
+				}
+			`,
+			expected: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			issues := runAnalyzer(t, tt.code)
+			if got := countMessages(issues, msg); got != tt.expected {
+				t.Errorf("got %d issues, want %d: %s", got, tt.expected, formatIssues(issues))
+			}
+		})
+	}
+}
+
+func TestCheckMainDeadlock(t *testing.T) {
+	const msg = "blocking receive in main with no goroutines will deadlock"
+
+	tests := []struct {
+		name     string
+		code     string
+		expected int
+	}{
+		{
+			name: "block forever without goroutines",
+			code: `
+				package main
+				func main() {
+					<-make(chan struct{})
+				}
+			`,
+			expected: 1,
+		},
+		{
+			name: "receive with no sender",
+			code: `
+				package main
+				func main() {
+					done := make(chan struct{})
+					<-done
+				}
+			`,
+			expected: 1,
+		},
+		{
+			name: "producing goroutine",
+			code: `
+				package main
+				func main() {
+					done := make(chan struct{})
+					go func() {
+						close(done)
+					}()
+					<-done
+				}
+			`,
+			expected: 0,
+		},
+		{
+			name: "signal channel",
+			code: `
+				package main
+				import (
+					"os"
+					"os/signal"
+				)
+				func main() {
+					sig := make(chan os.Signal, 1)
+					signal.Notify(sig, os.Interrupt)
+					<-sig
+				}
+			`,
+			expected: 0,
+		},
+		{
+			name: "not package main",
+			code: `
+				package test
+				func main() {
+					<-make(chan struct{})
+				}
+			`,
+			expected: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			issues := runAnalyzer(t, tt.code)
+			if got := countMessages(issues, msg); got != tt.expected {
+				t.Errorf("got %d issues, want %d: %s", got, tt.expected, formatIssues(issues))
+			}
+		})
+	}
+}
+
+func TestCheckAtomicMix(t *testing.T) {
+	const msg = "mixing channels and atomics for coordination — pick one"
+
+	tests := []struct {
+		name     string
+		code     string
+		expected int
+	}{
+		{
+			name: "channel and atomic",
+			code: `
+				package test
+				import "sync/atomic"
+				func run(jobs []int, results chan int) {
+					var pending int64
+					for _, job := range jobs {
+						atomic.AddInt64(&pending, 1)
+						go func() {
+							results <- job
+							atomic.AddInt64(&pending, -1)
+						}()
+					}
+				}
+			`,
+			expected: 1,
+		},
+		{
+			name: "channel only",
+			code: `
+				package test
+				func run(jobs []int, results chan int) {
+					for _, job := range jobs {
+						go func() {
+							results <- job
+						}()
+					}
+				}
+			`,
+			expected: 0,
+		},
+		{
+			name: "atomic only",
+			code: `
+				package test
+				import "sync/atomic"
+				func count(jobs []int) int64 {
+					var n int64
+					for range jobs {
+						atomic.AddInt64(&n, 1)
+					}
+					return atomic.LoadInt64(&n)
+				}
+			`,
+			expected: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			issues := runAnalyzerEnabled(t, tt.code, RuleAtomicMix)
+			if got := countMessages(issues, msg); got != tt.expected {
+				t.Errorf("got %d issues, want %d: %s", got, tt.expected, formatIssues(issues))
+			}
+		})
+	}
+}
+
+func TestCheckLoopVarCapture(t *testing.T) {
+	const code = `package test
+func run(jobs []int, results chan int) {
+	for _, job := range jobs {
+		go func() {
+			results <- job
+		}()
+	}
+	for i := 0; i < 3; i++ {
+		go func(i int) {
+			results <- i
+		}(i)
+	}
+	for _, job := range jobs {
+		job := job
+		go func() {
+			results <- job
+		}()
+	}
+}
+`
+	const msg = "goroutine captures loop variable job, shared by all iterations before Go 1.22 — pass it as an argument"
+
+	tests := []struct {
+		goVersion string
+		expected  int
+	}{
+		{goVersion: "go1.21", expected: 1},
+		{goVersion: "go1.22", expected: 0},
+		{goVersion: "go1.24", expected: 0},
+		{goVersion: "", expected: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.goVersion, func(t *testing.T) {
+			analyzer := &Analyzer{fset: token.NewFileSet(), goVersion: tt.goVersion}
+			if err := analyzer.analyzeSource("test.go", []byte(code)); err != nil {
+				t.Fatalf("analysis failed: %v", err)
+			}
+			if got := countMessages(analyzer.issues, msg); got != tt.expected {
+				t.Errorf("got %d issues, want %d: %s", got, tt.expected, formatIssues(analyzer.issues))
+			}
+		})
+	}
+}
+
+func TestCheckOverCapacity(t *testing.T) {
+	const msg = "more guaranteed sends than channel capacity will deadlock"
+
+	tests := []struct {
+		name     string
+		code     string
+		expected int
+	}{
+		{
+			name: "three sends into a buffer of two",
+			code: `
+				package test
+				func collect(a, b, c int) chan int {
+					ch := make(chan int, 2)
+					ch <- a
+					ch <- b
+					ch <- c
+					return ch
+				}
+			`,
+			expected: 1,
+		},
+		{
+			name: "sends within capacity",
+			code: `
+				package test
+				func collect(a, b int) chan int {
+					ch := make(chan int, 2)
+					ch <- a
+					ch <- b
+					return ch
+				}
+			`,
+			expected: 0,
+		},
+		{
+			name: "receive in between",
+			code: `
+				package test
+				func collect(a, b int) int {
+					ch := make(chan int, 1)
+					ch <- a
+					v := <-ch
+					ch <- b
+					return v
+				}
+			`,
+			expected: 0,
+		},
+		{
+			name: "consumer goroutine started first",
+			code: `
+				package test
+				func collect(a, b int, use func(int)) {
+					ch := make(chan int, 1)
+					go func() {
+						for v := range ch {
+							use(v)
+						}
+					}()
+					ch <- a
+					ch <- b
+				}
+			`,
+			expected: 0,
+		},
+		{
+			name: "conditional send",
+			code: `
+				package test
+				func collect(a, b int, ok bool) {
+					ch := make(chan int, 1)
+					ch <- a
+					if ok {
+						ch <- b
+					}
+				}
+			`,
+			expected: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			issues := runAnalyzer(t, tt.code)
+			if got := countMessages(issues, msg); got != tt.expected {
+				t.Errorf("got %d issues, want %d: %s", got, tt.expected, formatIssues(issues))
+			}
+		})
+	}
+}
+
+func TestCheckUnreadErrors(t *testing.T) {
+	const msg = "errors are sent on a channel that's never read"
+
+	tests := []struct {
+		name     string
+		code     string
+		expected int
+	}{
+		{
+			name: "swallowed errors",
+			code: `
+				package test
+				import "sync"
+				func runAll(tasks []func() error) {
+					errCh := make(chan error, len(tasks))
+					var wg sync.WaitGroup
+					for _, task := range tasks {
+						wg.Add(1)
+						go func() {
+							defer wg.Done()
+							if err := task(); err != nil {
+								errCh <- err
+							}
+						}()
+					}
+					wg.Wait()
+					close(errCh)
+				}
+			`,
+			expected: 1,
+		},
+		{
+			name: "drained errors",
+			code: `
+				package test
+				import "sync"
+				func runAll(tasks []func() error) error {
+					errCh := make(chan error, len(tasks))
+					var wg sync.WaitGroup
+					for _, task := range tasks {
+						wg.Add(1)
+						go func() {
+							defer wg.Done()
+							errCh <- task()
+						}()
+					}
+					wg.Wait()
+					close(errCh)
+					for err := range errCh {
+						if err != nil {
+							return err
+						}
+					}
+					return nil
+				}
+			`,
+			expected: 0,
+		},
+		{
+			name: "returned to the caller",
+			code: `
+				package test
+				func start(task func() error) <-chan error {
+					errCh := make(chan error, 1)
+					go func() {
+						errCh <- task()
+					}()
+					return errCh
+				}
+			`,
+			expected: 0,
+		},
+		{
+			name: "non-error channel",
+			code: `
+				package test
+				func start(task func() int) {
+					results := make(chan int, 1)
+					go func() {
+						results <- task()
+					}()
+				}
+			`,
+			expected: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			issues := runAnalyzer(t, tt.code)
+			if got := countMessages(issues, msg); got != tt.expected {
+				t.Errorf("got %d issues, want %d: %s", got, tt.expected, formatIssues(issues))
+			}
+		})
+	}
+}
+
+func TestCheckNilFieldSend(t *testing.T) {
+	const msg = "send on channel field s.events, which the struct literal leaves nil and nothing makes, blocks forever"
+
+	tests := []struct {
+		name     string
+		code     string
+		expected int
+	}{
+		{
+			name: "field omitted",
+			code: `
+				package test
+				type Server struct {
+					name   string
+					events chan int
+				}
+				func run() {
+					s := Server{name: "api"}
+					s.events <- 1
+				}
+			`,
+			expected: 1,
+		},
+		{
+			name: "field explicitly nil through a pointer",
+			code: `
+				package test
+				type Server struct{ events chan int }
+				func run() {
+					s := &Server{events: nil}
+					s.events <- 1
+				}
+			`,
+			expected: 1,
+		},
+		{
+			name: "field made in the literal",
+			code: `
+				package test
+				type Server struct{ events chan int }
+				func run() {
+					s := Server{events: make(chan int, 1)}
+					s.events <- 1
+				}
+			`,
+			expected: 0,
+		},
+		{
+			name: "field assigned later",
+			code: `
+				package test
+				type Server struct{ events chan int }
+				func run() {
+					s := Server{}
+					s.events = make(chan int, 1)
+					s.events <- 1
+				}
+			`,
+			expected: 0,
+		},
+		{
+			name: "initialized by a method",
+			code: `
+				package test
+				type Server struct{ events chan int }
+				func (s *Server) init() { s.events = make(chan int, 1) }
+				func run() {
+					s := &Server{}
+					s.init()
+					s.events <- 1
+				}
+			`,
+			expected: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			issues := runAnalyzer(t, tt.code)
+			if got := countMessages(issues, msg); got != tt.expected {
+				t.Errorf("got %d issues, want %d: %s", got, tt.expected, formatIssues(issues))
+			}
+		})
+	}
+}
+
+func TestCheckDropHandler(t *testing.T) {
+	const msg = "non-blocking send drops values without calling a drop handler (metrics.Dropped, *.RecordDrop)"
+
+	tests := []struct {
+		name     string
+		code     string
+		expected int
+	}{
+		{
+			name: "default calls the handler",
+			code: `
+				package test
+				func publish(ch chan int, v int) {
+					select {
+					case ch <- v:
+					default:
+						metrics.Dropped("events")
+					}
+				}
+			`,
+			expected: 0,
+		},
+		{
+			name: "default calls a matching method",
+			code: `
+				package test
+				func publish(ch chan int, v int, s *stats) {
+					select {
+					case ch <- v:
+					default:
+						if v > 0 {
+							s.RecordDrop()
+						}
+					}
+				}
+			`,
+			expected: 0,
+		},
+		{
+			name: "default only logs",
+			code: `
+				package test
+				import "log"
+				func publish(ch chan int, v int) {
+					select {
+					case ch <- v:
+					default:
+						log.Println("dropped")
+					}
+				}
+			`,
+			expected: 1,
+		},
+		{
+			name: "empty default",
+			code: `
+				package test
+				func publish(ch chan int, v int) {
+					select {
+					case ch <- v:
+					default:
+					}
+				}
+			`,
+			expected: 1,
+		},
+		{
+			name: "non-blocking receive",
+			code: `
+				package test
+				func poll(ch chan int) {
+					select {
+					case <-ch:
+					default:
+					}
+				}
+			`,
+			expected: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			analyzer := &Analyzer{fset: token.NewFileSet(), dropHandlers: []string{"metrics.Dropped", "*.RecordDrop"}}
+			if err := analyzer.analyzeSource("test.go", []byte(tt.code)); err != nil {
+				t.Fatalf("analysis failed: %v", err)
+			}
+			if got := countMessages(analyzer.issues, msg); got != tt.expected {
+				t.Errorf("got %d issues, want %d: %s", got, tt.expected, formatIssues(analyzer.issues))
+			}
+		})
+	}
+
+	if issues := runAnalyzer(t, tests[3].code); countMessages(issues, "without calling a drop handler") != 0 {
+		t.Errorf("drop-handler reported without -drop-handler: %s", formatIssues(issues))
+	}
+}
+
+func TestCheckHandlerSend(t *testing.T) {
+	const msg = "blocking channel send in HTTP handler ignores request cancellation"
+
+	tests := []struct {
+		name     string
+		code     string
+		expected int
+	}{
+		{
+			name: "unguarded send in handler",
+			code: `
+				package test
+				import "net/http"
+				var jobs = make(chan string, 1)
+				func submit(w http.ResponseWriter, r *http.Request) {
+					jobs <- r.URL.Path
+				}
+			`,
+			expected: 1,
+		},
+		{
+			name: "select without request context",
+			code: `
+				package test
+				import (
+					"net/http"
+					"time"
+				)
+				var jobs = make(chan string, 1)
+				func submit(w http.ResponseWriter, r *http.Request) {
+					select {
+					case jobs <- r.URL.Path:
+					case <-time.After(time.Second):
+					}
+				}
+			`,
+			expected: 1,
+		},
+		{
+			name: "select on request context",
+			code: `
+				package test
+				import "net/http"
+				var jobs = make(chan string, 1)
+				func submit(w http.ResponseWriter, r *http.Request) {
+					select {
+					case jobs <- r.URL.Path:
+					case <-r.Context().Done():
+						http.Error(w, "canceled", http.StatusServiceUnavailable)
+					}
+				}
+			`,
+			expected: 0,
+		},
+		{
+			name: "select on derived context",
+			code: `
+				package test
+				import (
+					"context"
+					"net/http"
+					"time"
+				)
+				var jobs = make(chan string, 1)
+				func submit(w http.ResponseWriter, req *http.Request) {
+					ctx, cancel := context.WithTimeout(req.Context(), time.Second)
+					defer cancel()
+					select {
+					case jobs <- req.URL.Path:
+					case <-ctx.Done():
+					}
+				}
+			`,
+			expected: 0,
+		},
+		{
+			name: "non-blocking send",
+			code: `
+				package test
+				import "net/http"
+				var jobs = make(chan string, 1)
+				func submit(w http.ResponseWriter, r *http.Request) {
+					select {
+					case jobs <- r.URL.Path:
+					default:
+						w.WriteHeader(http.StatusTooManyRequests)
+					}
+				}
+			`,
+			expected: 0,
+		},
+		{
+			name: "handler func literal",
+			code: `
+				package test
+				import "net/http"
+				func register(mux *http.ServeMux, jobs chan string) {
+					mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+						jobs <- r.URL.Path
+					})
+				}
+			`,
+			expected: 1,
+		},
+		{
+			name: "send in goroutine started by handler",
+			code: `
+				package test
+				import "net/http"
+				var jobs = make(chan string, 1)
+				func submit(w http.ResponseWriter, r *http.Request) {
+					path := r.URL.Path
+					go func() {
+						jobs <- path
+					}()
+				}
+			`,
+			expected: 0,
+		},
+		{
+			name: "not a handler",
+			code: `
+				package test
+				import "net/http"
+				func forward(r *http.Request, jobs chan string) {
+					jobs <- r.URL.Path
+				}
+			`,
+			expected: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			issues := runAnalyzer(t, tt.code)
+			if got := countMessages(issues, msg); got != tt.expected {
+				t.Errorf("got %d issues, want %d: %s", got, tt.expected, formatIssues(issues))
+			}
+		})
+	}
+}
+
+func TestCheckChanAsSlice(t *testing.T) {
+	const msg = "channel used as a buffer — a slice may be clearer"
+
+	tests := []struct {
+		name     string
+		code     string
+		expected int
+	}{
+		{
+			name: "fill, close and drain",
+			code: `
+				package test
+				func squares() []int {
+					ch := make(chan int, 10)
+					for i := 0; i < 10; i++ {
+						ch <- i * i
+					}
+					close(ch)
+					var out []int
+					for v := range ch {
+						out = append(out, v)
+					}
+					return out
+				}
+			`,
+			expected: 1,
+		},
+		{
+			name: "named constant and range over int",
+			code: `
+				package test
+				const n = 4
+				func total() int {
+					ch := make(chan int, n)
+					for i := range n {
+						ch <- i
+					}
+					close(ch)
+					sum := 0
+					for v := range ch {
+						sum += v
+					}
+					return sum
+				}
+			`,
+			expected: 1,
+		},
+		{
+			name: "concurrent producer",
+			code: `
+				package test
+				func squares() []int {
+					ch := make(chan int, 10)
+					go func() {
+						for i := 0; i < 10; i++ {
+							ch <- i * i
+						}
+						close(ch)
+					}()
+					var out []int
+					for v := range ch {
+						out = append(out, v)
+					}
+					return out
+				}
+			`,
+			expected: 0,
+		},
+		{
+			name: "fewer sends than capacity",
+			code: `
+				package test
+				func squares() []int {
+					ch := make(chan int, 16)
+					for i := 0; i < 10; i++ {
+						ch <- i * i
+					}
+					close(ch)
+					var out []int
+					for v := range ch {
+						out = append(out, v)
+					}
+					return out
+				}
+			`,
+			expected: 0,
+		},
+		{
+			name: "channel handed to a consumer",
+			code: `
+				package test
+				func feed(consume func(chan int)) {
+					ch := make(chan int, 3)
+					for i := 0; i < 3; i++ {
+						ch <- i
+					}
+					consume(ch)
+					close(ch)
+					for range ch {
+					}
+				}
+			`,
+			expected: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			issues := runAnalyzerEnabled(t, tt.code, RuleChanAsSlice)
+			if got := countMessages(issues, msg); got != tt.expected {
+				t.Errorf("got %d issues, want %d: %s", got, tt.expected, formatIssues(issues))
+			}
+		})
+	}
+
+	if issues := runAnalyzer(t, tests[0].code); countMessages(issues, msg) != 0 {
+		t.Errorf("opt-in rule reported without -enable: %s", formatIssues(issues))
+	}
+}
+
+func TestCheckRecvOperand(t *testing.T) {
+	const msg = "value from channel used as divisor/index without checking closed status"
+
+	tests := []struct {
+		name     string
+		code     string
+		expected int
+	}{
+		{
+			name: "divisor",
+			code: `
+				package test
+				func average(total int, counts chan int) int {
+					return total / <-counts
+				}
+			`,
+			expected: 1,
+		},
+		{
+			name: "parenthesized remainder",
+			code: `
+				package test
+				func bucket(hash int, sizes chan int) int {
+					return hash % (<-sizes)
+				}
+			`,
+			expected: 1,
+		},
+		{
+			name: "divide assignment",
+			code: `
+				package test
+				func scale(total int, counts chan int) int {
+					total /= <-counts
+					return total
+				}
+			`,
+			expected: 1,
+		},
+		{
+			name: "index",
+			code: `
+				package test
+				func pick(items []string, picks chan int) string {
+					return items[<-picks]
+				}
+			`,
+			expected: 1,
+		},
+		{
+			name: "dividend",
+			code: `
+				package test
+				func half(values chan int) int {
+					return <-values / 2
+				}
+			`,
+			expected: 0,
+		},
+		{
+			name: "comma-ok guarded divisor",
+			code: `
+				package test
+				func average(total int, counts chan int) int {
+					n, ok := <-counts
+					if !ok || n == 0 {
+						return 0
+					}
+					return total / n
+				}
+			`,
+			expected: 0,
+		},
+		{
+			name: "comma-ok guarded index",
+			code: `
+				package test
+				func pick(items []string, picks chan int) string {
+					i, ok := <-picks
+					if !ok {
+						return ""
+					}
+					return items[i]
+				}
+			`,
+			expected: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			issues := runAnalyzer(t, tt.code)
+			if got := countMessages(issues, msg); got != tt.expected {
+				t.Errorf("got %d issues, want %d: %s", got, tt.expected, formatIssues(issues))
+			}
+		})
+	}
+}
+
+func TestCheckOnceBlocking(t *testing.T) {
+	const msg = "blocking channel op inside sync.Once.Do can deadlock subsequent callers"
+
+	tests := []struct {
+		name     string
+		code     string
+		expected int
+	}{
+		{
+			name: "blocking send",
+			code: `
+				package test
+				import "sync"
+				type server struct {
+					once  sync.Once
+					ready chan struct{}
+				}
+				func (s *server) start() {
+					s.once.Do(func() {
+						s.ready <- struct{}{}
+					})
+				}
+			`,
+			expected: 1,
+		},
+		{
+			name: "blocking receive",
+			code: `
+				package test
+				import "sync"
+				var once sync.Once
+				var config string
+				func load(configs chan string) {
+					once.Do(func() {
+						config = <-configs
+					})
+				}
+			`,
+			expected: 1,
+		},
+		{
+			name: "select-guarded send",
+			code: `
+				package test
+				import (
+					"sync"
+					"time"
+				)
+				func start(once *sync.Once, ready chan struct{}) {
+					once.Do(func() {
+						select {
+						case ready <- struct{}{}:
+						case <-time.After(time.Second):
+						}
+					})
+				}
+			`,
+			expected: 0,
+		},
+		{
+			name: "send in goroutine started by Do",
+			code: `
+				package test
+				import "sync"
+				func start(once *sync.Once, ready chan struct{}) {
+					once.Do(func() {
+						go func() {
+							ready <- struct{}{}
+						}()
+					})
+				}
+			`,
+			expected: 0,
+		},
+		{
+			name: "Do on another type",
+			code: `
+				package test
+				type runner struct{}
+				func (runner) Do(f func()) { f() }
+				func start(r runner, ready chan struct{}) {
+					r.Do(func() {
+						ready <- struct{}{}
+					})
+				}
+			`,
+			expected: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			issues := runAnalyzer(t, tt.code)
+			if got := countMessages(issues, msg); got != tt.expected {
+				t.Errorf("got %d issues, want %d: %s", got, tt.expected, formatIssues(issues))
+			}
+		})
+	}
+}
+
+func TestCheckImmediateClose(t *testing.T) {
+	const msg = "channel closed immediately after creation with no sends — intended as a pre-closed signal?"
+
+	tests := []struct {
+		name     string
+		code     string
+		expected int
+	}{
+		{
+			name: "closed right after make",
+			code: `
+				package test
+				func start() <-chan struct{} {
+					done := make(chan struct{})
+					close(done)
+					return done
+				}
+			`,
+			expected: 1,
+		},
+		{
+			name: "var declaration",
+			code: `
+				package test
+				func wait() {
+					var ready = make(chan struct{})
+					close(ready)
+					<-ready
+				}
+			`,
+			expected: 1,
+		},
+		{
+			name: "create, use, close",
+			code: `
+				package test
+				func run(work func()) {
+					done := make(chan struct{})
+					go func() {
+						work()
+						close(done)
+					}()
+					<-done
+				}
+			`,
+			expected: 0,
+		},
+		{
+			name: "sent on before close",
+			code: `
+				package test
+				func one() chan int {
+					ch := make(chan int, 1)
+					ch <- 1
+					close(ch)
+					return ch
+				}
+			`,
+			expected: 0,
+		},
+		{
+			name: "statements in between",
+			code: `
+				package test
+				func start(register func(chan struct{})) {
+					done := make(chan struct{})
+					register(done)
+					close(done)
+				}
+			`,
+			expected: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			issues := runAnalyzer(t, tt.code)
+			if got := countMessages(issues, msg); got != tt.expected {
+				t.Errorf("got %d issues, want %d: %s", got, tt.expected, formatIssues(issues))
+			}
+		})
+	}
+}
+
+func TestCheckPackageChan(t *testing.T) {
+	const msg = "operating on a package-level channel — ensure lifecycle and ownership are clear"
+
+	tests := []struct {
+		name     string
+		code     string
+		expected int
+	}{
+		{
+			name: "send and receive on a package-level channel",
+			code: `
+				package test
+				var events = make(chan string, 16)
+				func publish(e string) {
+					events <- e
+				}
+				func next() string {
+					return <-events
+				}
+			`,
+			expected: 2,
+		},
+		{
+			name: "local channel",
+			code: `
+				package test
+				func roundTrip(e string) string {
+					events := make(chan string, 1)
+					events <- e
+					return <-events
+				}
+			`,
+			expected: 0,
+		},
+		{
+			name: "local shadows package-level channel",
+			code: `
+				package test
+				var events = make(chan string, 16)
+				func roundTrip(e string) string {
+					events := make(chan string, 1)
+					events <- e
+					return <-events
+				}
+			`,
+			expected: 0,
+		},
+		{
+			name: "struct field",
+			code: `
+				package test
+				type bus struct{ events chan string }
+				func (b *bus) publish(e string) {
+					b.events <- e
+				}
+			`,
+			expected: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			issues := runAnalyzer(t, tt.code)
+			if got := countMessages(issues, msg); got != tt.expected {
+				t.Errorf("got %d issues, want %d: %s", got, tt.expected, formatIssues(issues))
+			}
+		})
+	}
+}
+
+func TestCheckUnawaitedReply(t *testing.T) {
+	const msg = "request sent but reply channel is never awaited"
+
+	tests := []struct {
+		name     string
+		code     string
+		expected int
+	}{
+		{
+			name: "reply never awaited",
+			code: `
+				package test
+				type request struct {
+					key   string
+					reply chan string
+				}
+				func lookup(requests chan request, key string) {
+					reply := make(chan string)
+					requests <- request{key: key, reply: reply}
+				}
+			`,
+			expected: 1,
+		},
+		{
+			name: "pointer request never awaited",
+			code: `
+				package test
+				type request struct {
+					key   string
+					reply chan string
+				}
+				func lookup(requests chan *request, key string) {
+					reply := make(chan string)
+					requests <- &request{key, reply}
+				}
+			`,
+			expected: 1,
+		},
+		{
+			name: "complete handshake",
+			code: `
+				package test
+				type request struct {
+					key   string
+					reply chan string
+				}
+				func lookup(requests chan request, key string) string {
+					reply := make(chan string)
+					requests <- request{key: key, reply: reply}
+					return <-reply
+				}
+			`,
+			expected: 0,
+		},
+		{
+			name: "reply awaited in select",
+			code: `
+				package test
+				import "time"
+				type request struct {
+					key   string
+					reply chan string
+				}
+				func lookup(requests chan request, key string) string {
+					reply := make(chan string)
+					requests <- request{key: key, reply: reply}
+					select {
+					case v := <-reply:
+						return v
+					case <-time.After(time.Second):
+						return ""
+					}
+				}
+			`,
+			expected: 0,
+		},
+		{
+			name: "reply handed to the caller",
+			code: `
+				package test
+				type request struct {
+					key   string
+					reply chan string
+				}
+				func lookup(requests chan request, key string) <-chan string {
+					reply := make(chan string)
+					requests <- request{key: key, reply: reply}
+					return reply
+				}
+			`,
+			expected: 0,
+		},
+		{
+			name: "buffered reply",
+			code: `
+				package test
+				type request struct {
+					key   string
+					reply chan string
+				}
+				func lookup(requests chan request, key string) {
+					reply := make(chan string, 1)
+					requests <- request{key: key, reply: reply}
+				}
+			`,
+			expected: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			issues := runAnalyzer(t, tt.code)
+			if got := countMessages(issues, msg); got != tt.expected {
+				t.Errorf("got %d issues, want %d: %s", got, tt.expected, formatIssues(issues))
+			}
+		})
+	}
+}
+
+func TestCheckSendBeforeGo(t *testing.T) {
+	const msg = "send precedes the goroutine meant to receive it — deadlock"
+
+	tests := []struct {
+		name     string
+		code     string
+		expected int
+	}{
+		{
+			name: "send before go",
+			code: `
+				package test
+				func consume(ch chan int) { <-ch }
+				func run() {
+					ch := make(chan int)
+					ch <- 1
+					go consume(ch)
+				}
+			`,
+			expected: 1,
+		},
+		{
+			name: "send before go func literal",
+			code: `
+				package test
+				func run() {
+					ch := make(chan int)
+					ch <- 1
+					go func() {
+						<-ch
+					}()
+				}
+			`,
+			expected: 1,
+		},
+		{
+			name: "go before send",
+			code: `
+				package test
+				func consume(ch chan int) { <-ch }
+				func run() {
+					ch := make(chan int)
+					go consume(ch)
+					ch <- 1
+				}
+			`,
+			expected: 0,
+		},
+		{
+			name: "buffered channel",
+			code: `
+				package test
+				func consume(ch chan int) { <-ch }
+				func run() {
+					ch := make(chan int, 1)
+					ch <- 1
+					go consume(ch)
+				}
+			`,
+			expected: 0,
+		},
+		{
+			name: "consumer registered earlier",
+			code: `
+				package test
+				func run(register func(chan int)) {
+					ch := make(chan int)
+					register(ch)
+					ch <- 1
+					go func() { <-ch }()
+				}
+			`,
+			expected: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			issues := runAnalyzer(t, tt.code)
+			if got := countMessages(issues, msg); got != tt.expected {
+				t.Errorf("got %d issues, want %d: %s", got, tt.expected, formatIssues(issues))
+			}
+		})
+	}
+}
+
+func TestCheckChanOfChan(t *testing.T) {
+	const msg = "channel of channels — document request/reply ownership"
+
+	tests := []struct {
+		name     string
+		code     string
+		expected int
+	}{
+		{
+			name: "chan chan int",
+			code: `
+				package test
+				func serve() chan chan int {
+					return make(chan chan int, 1)
+				}
+			`,
+			expected: 1,
+		},
+		{
+			name: "directional inner channel",
+			code: `
+				package test
+				func serve() {
+					requests := make(chan (<-chan int))
+					_ = requests
+				}
+			`,
+			expected: 1,
+		},
+		{
+			name: "chan int",
+			code: `
+				package test
+				func serve() chan int {
+					return make(chan int, 1)
+				}
+			`,
+			expected: 0,
+		},
+		{
+			name: "struct carrying a reply channel",
+			code: `
+				package test
+				type request struct{ reply chan int }
+				func serve() chan request {
+					return make(chan request, 1)
+				}
+			`,
+			expected: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			issues := runAnalyzerEnabled(t, tt.code, RuleChanOfChan)
+			if got := countMessages(issues, msg); got != tt.expected {
+				t.Errorf("got %d issues, want %d: %s", got, tt.expected, formatIssues(issues))
+			}
+		})
+	}
+
+	if issues := runAnalyzer(t, tests[0].code); countMessages(issues, msg) != 0 {
+		t.Errorf("opt-in rule reported without -enable: %s", formatIssues(issues))
+	}
+}
+
+func TestCheckShadowedCaseSend(t *testing.T) {
+	const msg = "send targets a variable shadowed by this select case's receive"
+
+	tests := []struct {
+		name     string
+		code     string
+		expected int
+	}{
+		{
+			name: "send on shadowed channel",
+			code: `
+				package test
+				func relay(ch chan int, in chan chan int) {
+					select {
+					case ch := <-in:
+						ch <- 1
+					}
+				}
+			`,
+			expected: 1,
+		},
+		{
+			name: "send on outer channel",
+			code: `
+				package test
+				func relay(out chan int, in chan int) {
+					select {
+					case v := <-in:
+						out <- v
+					}
+				}
+			`,
+			expected: 0,
+		},
+		{
+			name: "received reply channel without an outer variable",
+			code: `
+				package test
+				func serve(requests chan chan int) {
+					select {
+					case reply := <-requests:
+						reply <- 42
+					}
+				}
+			`,
+			expected: 0,
+		},
+		{
+			name: "send in goroutine started by the case",
+			code: `
+				package test
+				func relay(ch chan int, in chan chan int) {
+					select {
+					case ch := <-in:
+						go func(ch chan int) {
+							ch <- 1
+						}(ch)
+					}
+				}
+			`,
+			expected: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			issues := runAnalyzer(t, tt.code)
+			if got := countMessages(issues, msg); got != tt.expected {
+				t.Errorf("got %d issues, want %d: %s", got, tt.expected, formatIssues(issues))
+			}
+		})
+	}
+}
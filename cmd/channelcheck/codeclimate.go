@@ -0,0 +1,82 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+)
+
+// Code Climate engine issue types. The report is a JSON array of these
+// issues; see https://github.com/codeclimate/platform/blob/master/spec/analyzers/SPEC.md.
+// It is close to the GitLab Code Quality format, which is derived from it,
+// but carries the type, categories and full line range Code Climate expects.
+type (
+	codeClimateIssue struct {
+		Type        string              `json:"type"`
+		CheckName   string              `json:"check_name"`
+		Description string              `json:"description"`
+		Categories  []string            `json:"categories"`
+		Location    codeClimateLocation `json:"location"`
+		Severity    string              `json:"severity"`
+		Fingerprint string              `json:"fingerprint"`
+	}
+
+	codeClimateLocation struct {
+		Path  string           `json:"path"`
+		Lines codeClimateLines `json:"lines"`
+	}
+
+	codeClimateLines struct {
+		Begin int `json:"begin"`
+		End   int `json:"end"`
+	}
+)
+
+// codeClimateSeverity maps a severity to a Code Climate severity.
+func codeClimateSeverity(severity string) string {
+	switch severity {
+	case "ERROR":
+		return "critical"
+	case "WARNING":
+		return "major"
+	default:
+		return "info"
+	}
+}
+
+// codeClimateCategories returns the Code Climate categories of an issue:
+// INFO findings are about clarity, the others are bug risks.
+func codeClimateCategories(severity string) []string {
+	if severity == "INFO" {
+		return []string{"Clarity"}
+	}
+	return []string{"Bug Risk"}
+}
+
+// printCodeClimate writes the issues as a Code Climate report.
+func printCodeClimate(w io.Writer, issues []Issue) error {
+	report := make([]codeClimateIssue, len(issues))
+	for i, issue := range issues {
+		report[i] = codeClimateIssue{
+			Type:        "issue",
+			CheckName:   issue.Rule,
+			Description: issue.Message,
+			Categories:  codeClimateCategories(issue.Severity),
+			Location: codeClimateLocation{
+				Path:  filepath.ToSlash(issue.Pos.Filename),
+				Lines: codeClimateLines{Begin: issue.Pos.StartLine, End: issue.Pos.EndLine},
+			},
+			Severity:    codeClimateSeverity(issue.Severity),
+			Fingerprint: issue.Fingerprint(),
+		}
+	}
+
+	jsonBytes, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return fmt.Errorf("error marshaling Code Climate report: %w", err)
+	}
+
+	_, err = fmt.Fprintln(w, string(jsonBytes))
+	return err
+}
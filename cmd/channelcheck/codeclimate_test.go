@@ -0,0 +1,52 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"slices"
+	"testing"
+)
+
+func TestPrintCodeClimate(t *testing.T) {
+	var buf bytes.Buffer
+	if err := printOutput(&buf, OutputFormatCodeClimate, sampleIssues); err != nil {
+		t.Fatalf("printOutput failed: %v", err)
+	}
+
+	var report []codeClimateIssue
+	if err := json.Unmarshal(buf.Bytes(), &report); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if len(report) != len(sampleIssues) {
+		t.Fatalf("got %d issues, want %d", len(report), len(sampleIssues))
+	}
+	for i, issue := range sampleIssues {
+		got := report[i]
+		if got.Type != "issue" || got.Description != issue.Message || len(got.Categories) == 0 {
+			t.Errorf("issue %d is missing required fields: %+v", i, got)
+		}
+		if got.CheckName != issue.Rule {
+			t.Errorf("issue %d: got check_name %q, want rule %q", i, got.CheckName, issue.Rule)
+		}
+		if got.Fingerprint != issue.Fingerprint() {
+			t.Errorf("issue %d: got fingerprint %q, want %q", i, got.Fingerprint, issue.Fingerprint())
+		}
+		if got.Location.Path != issue.Pos.Filename || got.Location.Lines.Begin != issue.Pos.StartLine || got.Location.Lines.End != issue.Pos.EndLine {
+			t.Errorf("issue %d: got location %+v for %+v", i, got.Location, issue.Pos)
+		}
+	}
+	if report[0].Severity != "major" || report[1].Severity != "info" {
+		t.Errorf("got severities %q and %q, want major and info", report[0].Severity, report[1].Severity)
+	}
+	if !slices.Equal(report[0].Categories, []string{"Bug Risk"}) || !slices.Equal(report[1].Categories, []string{"Clarity"}) {
+		t.Errorf("got categories %v and %v", report[0].Categories, report[1].Categories)
+	}
+
+	buf.Reset()
+	if err := printCodeClimate(&buf, nil); err != nil {
+		t.Fatalf("printCodeClimate failed: %v", err)
+	}
+	if got := buf.String(); got != "[]\n" {
+		t.Errorf("empty report: got %q, want an empty array", got)
+	}
+}
@@ -0,0 +1,56 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// loadReport reads a report previously written with -output=json.
+func loadReport(path string) ([]Issue, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading report: %w", err)
+	}
+
+	var report JSONOutput
+	if err := json.Unmarshal(data, &report); err != nil {
+		return nil, fmt.Errorf("error parsing report %s: %w", path, err)
+	}
+
+	issues := make([]Issue, len(report.Issues))
+	for i, issue := range report.Issues {
+		issues[i] = Issue{
+			Rule:     issue.Rule,
+			Pos:      issue.Position,
+			Message:  issue.Message,
+			Severity: issue.Severity,
+			Func:     issue.Func,
+			Package:  issue.Package,
+		}
+	}
+	return issues, nil
+}
+
+// compareIssues returns the issues in current whose fingerprints do not
+// appear in previous, along with the number of previous fingerprints that
+// are no longer reported.
+func compareIssues(previous, current []Issue) ([]Issue, int) {
+	seen := make(map[string]bool, len(previous))
+	for _, issue := range previous {
+		seen[issue.Fingerprint()] = true
+	}
+
+	var newIssues []Issue
+	stillPresent := make(map[string]bool, len(current))
+	for _, issue := range current {
+		fingerprint := issue.Fingerprint()
+		if seen[fingerprint] {
+			stillPresent[fingerprint] = true
+			continue
+		}
+		newIssues = append(newIssues, issue)
+	}
+
+	return newIssues, len(seen) - len(stillPresent)
+}
@@ -0,0 +1,105 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// writeFile writes content to name inside dir and returns the full path.
+func writeFile(t *testing.T, dir, name, content string) string {
+	t.Helper()
+
+	path := filepath.Join(dir, name)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatalf("failed to create directory: %v", err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("failed to write %s: %v", name, err)
+	}
+	return path
+}
+
+func TestCompare(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "a.go", `package test
+func send(ch chan int) {
+	ch <- 1
+}
+`)
+
+	// Record the previous report.
+	var previous bytes.Buffer
+	if _, err := run([]string{"-path", dir, "-output", "json"}, &previous, &bytes.Buffer{}); err != nil {
+		t.Fatalf("run failed: %v", err)
+	}
+	reportPath := writeFile(t, t.TempDir(), "prev.json", previous.String())
+
+	// Add a new finding in another file; a.go is unchanged.
+	writeFile(t, dir, "b.go", `package test
+func other() {
+	ch := make(chan int, 1)
+	ch <- 1
+}
+`)
+
+	var stdout, stderr bytes.Buffer
+	if _, err := run([]string{"-path", dir, "-output", "json", "-compare", reportPath}, &stdout, &stderr); err != nil {
+		t.Fatalf("run failed: %v", err)
+	}
+
+	var output JSONOutput
+	if err := json.Unmarshal(stdout.Bytes(), &output); err != nil {
+		t.Fatalf("failed to parse output: %v", err)
+	}
+	if output.Total != 1 {
+		t.Fatalf("got %d issues, want only the new one: %+v", output.Total, output.Issues)
+	}
+	if got := filepath.Base(output.Issues[0].Position.Filename); got != "b.go" {
+		t.Errorf("got new issue in %s, want b.go", got)
+	}
+	if got := strings.TrimSpace(stderr.String()); got != "1 new, 0 resolved" {
+		t.Errorf("got summary %q, want %q", got, "1 new, 0 resolved")
+	}
+
+	// Fixing the original finding resolves it.
+	writeFile(t, dir, "a.go", "package test\n")
+	stdout.Reset()
+	stderr.Reset()
+	if _, err := run([]string{"-path", dir, "-compare", reportPath}, &stdout, &stderr); err != nil {
+		t.Fatalf("run failed: %v", err)
+	}
+	if got := strings.TrimSpace(stderr.String()); got != "1 new, 1 resolved" {
+		t.Errorf("got summary %q, want %q", got, "1 new, 1 resolved")
+	}
+}
+
+func TestFingerprint(t *testing.T) {
+	issue := Issue{
+		Rule:     RuleSendWithoutSelect,
+		Pos:      Position{Filename: "a.go", StartLine: 3, StartColumn: 2, EndLine: 3, EndColumn: 9},
+		Message:  "channel send without select statement may block indefinitely",
+		Severity: "WARNING",
+	}
+
+	moved := issue
+	moved.Pos.StartColumn = 4
+	if issue.Fingerprint() != moved.Fingerprint() {
+		t.Errorf("fingerprint changed with column only")
+	}
+
+	escalated := issue
+	escalated.Severity = "ERROR"
+	if issue.Fingerprint() != escalated.Fingerprint() {
+		t.Errorf("fingerprint changed with severity only")
+	}
+
+	other := issue
+	other.Pos.StartLine = 4
+	if issue.Fingerprint() == other.Fingerprint() {
+		t.Errorf("fingerprint did not change with line")
+	}
+}
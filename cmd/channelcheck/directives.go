@@ -0,0 +1,157 @@
+package main
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"slices"
+	"strings"
+)
+
+// defaultDirective names the comment directives understood by channelcheck,
+// e.g. //channelcheck:min-severity=error. -directive changes it.
+const defaultDirective = "channelcheck"
+
+// severityRank orders severities from least to most severe. Unknown
+// severities rank lowest.
+func severityRank(severity string) int {
+	switch strings.ToUpper(severity) {
+	case "INFO":
+		return 1
+	case "WARNING":
+		return 2
+	case "ERROR":
+		return 3
+	default:
+		return 0
+	}
+}
+
+// fileMinSeverity returns the severity set by a
+// //<directive>:min-severity=<severity> comment placed before the first
+// declaration of file, or "" if there is none.
+func fileMinSeverity(file *ast.File, directive string) (string, error) {
+	key := directive + ":min-severity="
+
+	for _, group := range file.Comments {
+		if len(file.Decls) > 0 && group.Pos() > file.Decls[0].Pos() {
+			break
+		}
+		for _, comment := range group.List {
+			text := strings.TrimSpace(strings.TrimPrefix(comment.Text, "//"))
+			value, ok := strings.CutPrefix(text, key)
+			if !ok {
+				continue
+			}
+			severity := strings.ToUpper(strings.TrimSpace(value))
+			if severityRank(severity) == 0 {
+				return "", fmt.Errorf("invalid min-severity %q: valid options are info, warning, error", value)
+			}
+			return severity, nil
+		}
+	}
+	return "", nil
+}
+
+// filterSeverity returns the issues at or above minSeverity.
+func filterSeverity(issues []Issue, minSeverity string) []Issue {
+	threshold := severityRank(minSeverity)
+
+	var result []Issue
+	for _, issue := range issues {
+		if severityRank(issue.Severity) >= threshold {
+			result = append(result, issue)
+		}
+	}
+	return result
+}
+
+// suppression is an inline //<directive>:ignore comment. A comment after
+// code suppresses the issues starting on its line; a comment on a line of its
+// own suppresses those on the next line.
+type suppression struct {
+	pos, end token.Pos
+	line     int
+	// rules limits the suppression to these rule IDs; empty means all.
+	rules []string
+}
+
+// matches reports whether s suppresses issue.
+func (s suppression) matches(issue Issue) bool {
+	if issue.Pos.StartLine != s.line {
+		return false
+	}
+	return len(s.rules) == 0 || slices.Contains(s.rules, issue.Rule)
+}
+
+// fileSuppressions returns the suppressions in file. A suppression is
+// written //<directive>:ignore, optionally followed by comma-separated rule
+// IDs and a reason, or just //<directive> for every rule.
+func fileSuppressions(fset *token.FileSet, file *ast.File, directive string) []suppression {
+	// firstCode holds the first position of code on each line, to tell
+	// trailing comments from comments on a line of their own.
+	firstCode := make(map[int]token.Pos)
+	record := func(pos token.Pos) {
+		line := fset.Position(pos).Line
+		if first, ok := firstCode[line]; !ok || pos < first {
+			firstCode[line] = pos
+		}
+	}
+	ast.Inspect(file, func(n ast.Node) bool {
+		switch n.(type) {
+		case nil, *ast.Comment, *ast.CommentGroup:
+			return false
+		}
+		record(n.Pos())
+		record(n.End() - 1)
+		return true
+	})
+
+	var suppressions []suppression
+	for _, group := range file.Comments {
+		for _, comment := range group.List {
+			text := strings.TrimSpace(strings.TrimPrefix(comment.Text, "//"))
+			var rules []string
+			if text != directive {
+				rest, ok := strings.CutPrefix(text, directive+":ignore")
+				if !ok || rest != "" && rest[0] != ' ' && rest[0] != '\t' {
+					continue
+				}
+				if fields := strings.Fields(rest); len(fields) > 0 {
+					rules = strings.Split(fields[0], ",")
+				}
+			}
+			line := fset.Position(comment.Pos()).Line
+			if first, ok := firstCode[line]; !ok || first > comment.Pos() {
+				line++
+			}
+			suppressions = append(suppressions, suppression{
+				pos:   comment.Pos(),
+				end:   comment.End(),
+				line:  line,
+				rules: rules,
+			})
+		}
+	}
+	return suppressions
+}
+
+// filterSuppressed returns the issues not matched by any suppression, and
+// which of the suppressions matched an issue.
+func filterSuppressed(issues []Issue, suppressions []suppression) ([]Issue, []bool) {
+	var result []Issue
+	used := make([]bool, len(suppressions))
+	for _, issue := range issues {
+		suppressed := false
+		for i, s := range suppressions {
+			if s.matches(issue) {
+				used[i] = true
+				suppressed = true
+			}
+		}
+		if !suppressed {
+			result = append(result, issue)
+		}
+	}
+	return result, used
+}
@@ -0,0 +1,212 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"go/token"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestFileMinSeverity(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "legacy.go", `//channelcheck:min-severity=error
+
+package test
+
+func legacy() {
+	ch := make(chan int)
+	ch <- 1
+}
+`)
+	writeFile(t, dir, "current.go", `package test
+
+func current() {
+	ch := make(chan int)
+	ch <- 1
+}
+`)
+
+	analyzer := &Analyzer{
+		fset: token.NewFileSet(),
+	}
+	if err := analyzer.analyzePath(context.Background(), dir); err != nil {
+		t.Fatalf("analysis failed: %v", err)
+	}
+
+	counts := make(map[string]int)
+	for _, issue := range analyzer.issues {
+		counts[filepath.Base(issue.Pos.Filename)]++
+	}
+	if counts["legacy.go"] != 0 {
+		t.Errorf("got %d issues in legacy.go, want 0: %s", counts["legacy.go"], formatIssues(analyzer.issues))
+	}
+	if counts["current.go"] != 2 {
+		t.Errorf("got %d issues in current.go, want 2: %s", counts["current.go"], formatIssues(analyzer.issues))
+	}
+}
+
+func TestFileMinSeverity_Warning(t *testing.T) {
+	issues := runAnalyzer(t, `// Package test is legacy code.
+//channelcheck:min-severity=warning
+package test
+
+func legacy() {
+	ch := make(chan int)
+	ch <- 1
+}
+`)
+	if len(issues) != 1 || issues[0].Severity != "WARNING" {
+		t.Errorf("expected only the WARNING issue, got: %s", formatIssues(issues))
+	}
+}
+
+func TestFileMinSeverity_Invalid(t *testing.T) {
+	analyzer := &Analyzer{
+		fset: token.NewFileSet(),
+	}
+	err := analyzer.analyzeSource("test.go", []byte("//channelcheck:min-severity=loud\npackage test\n"))
+	if err == nil {
+		t.Errorf("expected an error for an invalid severity")
+	}
+}
+
+func TestFileMinSeverity_IgnoredAfterDeclarations(t *testing.T) {
+	issues := runAnalyzer(t, `package test
+
+func f() {
+	ch := make(chan int)
+	//channelcheck:min-severity=error
+	ch <- 1
+}
+`)
+	if len(issues) != 2 {
+		t.Errorf("got %d issues, want 2: %s", len(issues), formatIssues(issues))
+	}
+}
+
+func TestSuppression(t *testing.T) {
+	tests := []struct {
+		name      string
+		directive string
+		code      string
+		want      []string
+	}{
+		{
+			name: "same line",
+			code: `package test
+func send() {
+	ch := make(chan int, 1) //channelcheck:ignore
+	ch <- 1
+}
+`,
+			want: []string{RuleSendWithoutSelect},
+		},
+		{
+			name: "line above, one rule",
+			code: `package test
+func send() {
+	ch := make(chan int)
+	//channelcheck:ignore send-without-select,busy-retry drained by the caller
+	ch <- 1
+}
+`,
+			want: []string{RuleUnbufferedChannel},
+		},
+		{
+			name: "other rule not suppressed",
+			code: `package test
+func send() {
+	ch := make(chan int) //channelcheck:ignore send-without-select
+	ch <- 1
+}
+`,
+			want: []string{RuleUnbufferedChannel, RuleSendWithoutSelect},
+		},
+		{
+			name:      "custom directive",
+			directive: "nolint:chancheck",
+			code: `package test
+func send() {
+	ch := make(chan int) //nolint:chancheck
+	ch <- 1 //channelcheck:ignore
+}
+`,
+			want: []string{RuleSendWithoutSelect},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			analyzer := &Analyzer{fset: token.NewFileSet(), directive: tt.directive}
+			if err := analyzer.analyzeSource("test.go", []byte(tt.code)); err != nil {
+				t.Fatalf("analysis failed: %v", err)
+			}
+			var got []string
+			for _, issue := range analyzer.issues {
+				got = append(got, issue.Rule)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUnusedSuppressions(t *testing.T) {
+	const code = `package test
+func send() {
+	ch := make(chan int, 1)
+	//channelcheck:ignore send-without-select drained by the caller
+	ch <- 1
+	//channelcheck:ignore fixed long ago
+	close(ch)
+}
+`
+	for _, report := range []bool{false, true} {
+		analyzer := &Analyzer{fset: token.NewFileSet(), reportUnusedSuppressions: report}
+		if err := analyzer.analyzeSource("test.go", []byte(code)); err != nil {
+			t.Fatalf("analysis failed: %v", err)
+		}
+
+		var unused []int
+		for _, issue := range analyzer.issues {
+			if issue.Rule == RuleUnusedSuppression {
+				unused = append(unused, issue.Pos.StartLine)
+				if issue.Severity != "WARNING" {
+					t.Errorf("got severity %s, want WARNING", issue.Severity)
+				}
+			} else {
+				t.Errorf("unexpected issue: %s", formatIssues([]Issue{issue}))
+			}
+		}
+		want := []int(nil)
+		if report {
+			want = []int{6}
+		}
+		if !slices.Equal(unused, want) {
+			t.Errorf("report=%v: got unused suppressions on lines %v, want %v", report, unused, want)
+		}
+	}
+}
+
+func TestRun_Directive(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "a.go", `//chancheck:min-severity=error
+package test
+
+func send(ch chan int) {
+	ch <- 1
+}
+`)
+
+	var stdout bytes.Buffer
+	if _, err := run([]string{"-path", dir, "-directive", "chancheck"}, &stdout, &bytes.Buffer{}); err != nil {
+		t.Fatalf("run failed: %v", err)
+	}
+	if !strings.Contains(stdout.String(), "No issues found") {
+		t.Errorf("custom min-severity directive not applied:\n%s", stdout.String())
+	}
+}
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// exitFailure is the exit code for a run whose findings fail the policy.
+const exitFailure = 1
+
+// exitPolicy decides the process exit code from the reported issues.
+type exitPolicy struct {
+	// failOn is the lowest severity that fails the run, or "" to never
+	// fail because of findings.
+	failOn string
+	// exitZero forces a zero exit code regardless of findings.
+	exitZero bool
+	// strict fails the run on any issue, whatever its severity.
+	strict bool
+	// failOnParseError fails the run if any file failed to parse.
+	// Parse errors are reported as parse-error issues but are otherwise
+	// left out of the policy, so -fail-on and -strict only see findings.
+	failOnParseError bool
+	// maxCount caps the number of issues per severity, from -max-errors,
+	// -max-warnings and -max-infos. Exceeding any cap fails the run.
+	// Severities without a cap are missing from the map.
+	maxCount map[string]int
+}
+
+// parseFailOn validates a -fail-on value and returns the severity it names,
+// or "" for none.
+func parseFailOn(value string) (string, error) {
+	if strings.EqualFold(value, "none") {
+		return "", nil
+	}
+	severity := strings.ToUpper(value)
+	if severityRank(severity) == 0 {
+		return "", fmt.Errorf("invalid -fail-on value %q: valid options are none, info, warning, error", value)
+	}
+	return severity, nil
+}
+
+// exitCode returns the exit code for a run that reported issues.
+//
+// Precedence, highest first:
+//  1. -exit-zero: always exit 0.
+//  2. -strict: exit 1 if there is any issue.
+//  3. -fail-on: exit 1 if any issue is at or above the severity.
+//  4. -max-errors, -max-warnings, -max-infos: exit 1 if more issues than
+//     the cap have that severity.
+//
+// parse-error issues only count for -fail-on-parse-error, which exits 1 if
+// there is any.
+func exitCode(issues []Issue, policy exitPolicy) int {
+	if policy.exitZero {
+		return 0
+	}
+
+	threshold := severityRank(policy.failOn)
+	counts := make(map[string]int)
+	for _, issue := range issues {
+		if issue.Rule == RuleParseError {
+			if policy.failOnParseError {
+				return exitFailure
+			}
+			continue
+		}
+		if policy.strict || (policy.failOn != "" && severityRank(issue.Severity) >= threshold) {
+			return exitFailure
+		}
+		counts[issue.Severity]++
+	}
+
+	for severity, limit := range policy.maxCount {
+		if counts[severity] > limit {
+			return exitFailure
+		}
+	}
+	return 0
+}
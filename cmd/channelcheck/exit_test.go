@@ -0,0 +1,300 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestExitCode(t *testing.T) {
+	issues := []Issue{
+		{Rule: RuleUnbufferedChannel, Severity: "INFO"},
+		{Rule: RuleSendWithoutSelect, Severity: "WARNING"},
+	}
+
+	tests := []struct {
+		name     string
+		issues   []Issue
+		policy   exitPolicy
+		expected int
+	}{
+		{
+			name:     "no issues",
+			issues:   nil,
+			policy:   exitPolicy{failOn: "INFO"},
+			expected: 0,
+		},
+		{
+			name:     "issues below the threshold",
+			issues:   issues,
+			policy:   exitPolicy{failOn: "ERROR"},
+			expected: 0,
+		},
+		{
+			name:     "strict threshold fails",
+			issues:   issues,
+			policy:   exitPolicy{failOn: "WARNING"},
+			expected: exitFailure,
+		},
+		{
+			name:     "exit-zero overrides the threshold",
+			issues:   issues,
+			policy:   exitPolicy{failOn: "INFO", exitZero: true},
+			expected: 0,
+		},
+		{
+			name:     "fail-on none",
+			issues:   issues,
+			policy:   exitPolicy{},
+			expected: 0,
+		},
+		{
+			name:     "strict fails on any issue",
+			issues:   issues[:1],
+			policy:   exitPolicy{failOn: "ERROR", strict: true},
+			expected: exitFailure,
+		},
+		{
+			name:     "strict without issues",
+			issues:   nil,
+			policy:   exitPolicy{strict: true},
+			expected: 0,
+		},
+		{
+			name:     "parse errors do not count for strict",
+			issues:   []Issue{{Rule: RuleParseError, Severity: "ERROR"}},
+			policy:   exitPolicy{strict: true, failOn: "ERROR"},
+			expected: 0,
+		},
+		{
+			name:     "at the warnings cap",
+			issues:   issues,
+			policy:   exitPolicy{maxCount: map[string]int{"WARNING": 1}},
+			expected: 0,
+		},
+		{
+			name:     "over the infos cap",
+			issues:   issues,
+			policy:   exitPolicy{maxCount: map[string]int{"INFO": 0}},
+			expected: exitFailure,
+		},
+		{
+			name:     "fail on parse error",
+			issues:   []Issue{{Rule: RuleParseError, Severity: "ERROR"}},
+			policy:   exitPolicy{failOnParseError: true},
+			expected: exitFailure,
+		},
+		{
+			name:     "exit-zero overrides strict",
+			issues:   issues,
+			policy:   exitPolicy{strict: true, exitZero: true},
+			expected: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := exitCode(tt.issues, tt.policy); got != tt.expected {
+				t.Errorf("got exit code %d, want %d", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestRun_ExitCode(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "a.go", `package test
+func send(ch chan int) {
+	ch <- 1
+}
+`)
+
+	tests := []struct {
+		name     string
+		args     []string
+		expected int
+	}{
+		{name: "default", args: nil, expected: 0},
+		{name: "fail on warning", args: []string{"-fail-on", "warning"}, expected: exitFailure},
+		{name: "exit-zero with issues", args: []string{"-fail-on", "warning", "-exit-zero"}, expected: 0},
+		{name: "strict", args: []string{"-strict"}, expected: exitFailure},
+		{name: "warn-as-error on the reported rule", args: []string{"-warn-as-error", RuleSendWithoutSelect}, expected: exitFailure},
+		{name: "warn-as-error on other rules", args: []string{"-warn-as-error", RuleBusyRetry, "-warn-as-error", RuleSendUnderLock}, expected: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			args := append([]string{"-path", dir}, tt.args...)
+			code, err := run(args, &bytes.Buffer{}, &bytes.Buffer{})
+			if err != nil {
+				t.Fatalf("run failed: %v", err)
+			}
+			if code != tt.expected {
+				t.Errorf("got exit code %d, want %d", code, tt.expected)
+			}
+		})
+	}
+}
+
+func TestRun_WarnAsError(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "a.go", `package test
+func send() {
+	ch := make(chan int)
+	ch <- 1
+}
+`)
+
+	var stdout bytes.Buffer
+	if _, err := run([]string{"-path", dir, "-warn-as-error", RuleSendWithoutSelect}, &stdout, &bytes.Buffer{}); err != nil {
+		t.Fatalf("run failed: %v", err)
+	}
+	if got := stdout.String(); !strings.Contains(got, "[ERROR]") || !strings.Contains(got, "[INFO]") {
+		t.Errorf("expected only the promoted rule to be reported as ERROR, got:\n%s", got)
+	}
+
+	if _, err := run([]string{"-path", dir, "-warn-as-error", "no-such-rule"}, &bytes.Buffer{}, &bytes.Buffer{}); err == nil {
+		t.Errorf("expected an error for an unknown rule")
+	}
+}
+
+func TestParseFailOn(t *testing.T) {
+	if _, err := parseFailOn("fatal"); err == nil {
+		t.Errorf("expected an error for an unknown severity")
+	}
+	if got, err := parseFailOn("none"); err != nil || got != "" {
+		t.Errorf("got %q, %v; want no threshold", got, err)
+	}
+	if got, err := parseFailOn("warning"); err != nil || got != "WARNING" {
+		t.Errorf("got %q, %v; want WARNING", got, err)
+	}
+}
+
+func TestRun_FailOnParseError(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "a.go", `package test
+func send(ch chan int) {
+	ch <- 1
+}
+`)
+	writeFile(t, dir, "broken.go", `package test
+func broken( {
+`)
+
+	tests := []struct {
+		name     string
+		args     []string
+		expected int
+	}{
+		{name: "default", args: nil, expected: 0},
+		{name: "fail-on-parse-error", args: []string{"-fail-on-parse-error"}, expected: exitFailure},
+		{name: "fail-on-parse-error with fail-on none", args: []string{"-fail-on", "none", "-fail-on-parse-error"}, expected: exitFailure},
+		{name: "exit-zero overrides fail-on-parse-error", args: []string{"-fail-on-parse-error", "-exit-zero"}, expected: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var stdout bytes.Buffer
+			args := append([]string{"-path", dir, "-output", "json"}, tt.args...)
+			code, err := run(args, &stdout, &bytes.Buffer{})
+			if err != nil {
+				t.Fatalf("run failed: %v", err)
+			}
+			if code != tt.expected {
+				t.Errorf("got exit code %d, want %d", code, tt.expected)
+			}
+
+			var output JSONOutput
+			if err := json.Unmarshal(stdout.Bytes(), &output); err != nil {
+				t.Fatalf("stdout is not the JSON report: %v\n%s", err, stdout.String())
+			}
+			rules := make(map[string]int)
+			for _, issue := range output.Issues {
+				rules[issue.Rule]++
+			}
+			if rules[RuleParseError] != 1 || rules[RuleSendWithoutSelect] != 1 {
+				t.Errorf("got issues by rule %v, want one parse-error and the finding in a.go", rules)
+			}
+		})
+	}
+}
+
+func TestRun_FailOnParseError_SingleFile(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "broken.go", `package test
+func broken( {
+`)
+	path := filepath.Join(dir, "broken.go")
+
+	tests := []struct {
+		name     string
+		args     []string
+		expected int
+	}{
+		{name: "default", args: nil, expected: 0},
+		{name: "fail-on-parse-error", args: []string{"-fail-on-parse-error"}, expected: exitFailure},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var stdout bytes.Buffer
+			args := append([]string{"-path", path, "-output", "json"}, tt.args...)
+			code, err := run(args, &stdout, &bytes.Buffer{})
+			if err != nil {
+				t.Fatalf("run failed: %v", err)
+			}
+			if code != tt.expected {
+				t.Errorf("got exit code %d, want %d", code, tt.expected)
+			}
+
+			var output JSONOutput
+			if err := json.Unmarshal(stdout.Bytes(), &output); err != nil {
+				t.Fatalf("stdout is not the JSON report: %v\n%s", err, stdout.String())
+			}
+			if len(output.Issues) != 1 || output.Issues[0].Rule != RuleParseError {
+				t.Errorf("got issues %+v, want one parse-error", output.Issues)
+			}
+		})
+	}
+}
+
+func TestRun_MaxWarnings(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "a.go", `package test
+func send(a, b chan int) {
+	a <- 1
+	b <- 2
+}
+`)
+
+	tests := []struct {
+		name     string
+		args     []string
+		expected int
+	}{
+		{name: "just under the cap", args: []string{"-max-warnings", "2"}, expected: 0},
+		{name: "just over the cap", args: []string{"-max-warnings", "1"}, expected: exitFailure},
+		{name: "other severities uncapped", args: []string{"-max-errors", "0", "-max-infos", "0"}, expected: 0},
+		{name: "no cap", args: []string{"-max-warnings", "-1"}, expected: 0},
+		{name: "exit-zero overrides the cap", args: []string{"-max-warnings", "0", "-exit-zero"}, expected: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			args := append([]string{"-path", dir}, tt.args...)
+			code, err := run(args, &bytes.Buffer{}, &bytes.Buffer{})
+			if err != nil {
+				t.Fatalf("run failed: %v", err)
+			}
+			if code != tt.expected {
+				t.Errorf("got exit code %d, want %d", code, tt.expected)
+			}
+		})
+	}
+
+	if _, err := run([]string{"-path", dir, "-max-infos", "-2"}, &bytes.Buffer{}, &bytes.Buffer{}); err == nil {
+		t.Errorf("expected an error for a negative cap")
+	}
+}
@@ -0,0 +1,359 @@
+package main
+
+import (
+	"bytes"
+	"cmp"
+	"fmt"
+	"go/ast"
+	"io"
+	"os"
+	"slices"
+	"strings"
+)
+
+// textEdit replaces the bytes from start to end of a file's source with
+// text. Fixes are edits rather than AST rewrites so the rest of the file
+// keeps its formatting.
+type textEdit struct {
+	filename   string
+	start, end int
+	text       string
+}
+
+// bufferFix returns a fix that rewrites the unbuffered make(chan T) call to
+// make(chan T, 1), or nil if fixes are disabled or the rewrite may not be
+// safe.
+//
+// The rewrite is only offered for a channel assigned to a local variable
+// that has exactly one send site outside any loop, the common "result
+// channel" shape. A buffer of one then lets the single sender finish even if
+// nobody receives, instead of leaking, without changing how many values can
+// be in flight.
+func (a *Analyzer) bufferFix(node *ast.CallExpr) *textEdit {
+	if !a.fix || len(a.stack.nodes) < 2 {
+		return nil
+	}
+
+	var name string
+	switch parent := a.stack.nodes[len(a.stack.nodes)-2].(type) {
+	case *ast.AssignStmt:
+		if len(parent.Lhs) == 1 && len(parent.Rhs) == 1 {
+			if ident, ok := parent.Lhs[0].(*ast.Ident); ok {
+				name = ident.Name
+			}
+		}
+	case *ast.ValueSpec:
+		if len(parent.Names) == 1 && len(parent.Values) == 1 {
+			name = parent.Names[0].Name
+		}
+	}
+	body := a.enclosingFuncBody()
+	if name == "" || name == "_" || body == nil || !singleSendOutsideLoop(body, name) {
+		return nil
+	}
+
+	// Insert after the type rather than before the closing parenthesis, so
+	// a trailing comma is kept valid.
+	end := a.fset.Position(node.Args[0].End())
+	return &textEdit{filename: end.Filename, start: end.Offset, end: end.Offset, text: ", 1"}
+}
+
+// singleSendOutsideLoop reports whether body contains exactly one send on the
+// variable name, and that send is not inside a loop.
+func singleSendOutsideLoop(body *ast.BlockStmt, name string) bool {
+	sends := 0
+	inLoop := false
+	var loops int
+	var stack []ast.Node
+	ast.Inspect(body, func(n ast.Node) bool {
+		if n == nil {
+			top := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			switch top.(type) {
+			case *ast.ForStmt, *ast.RangeStmt:
+				loops--
+			}
+			return true
+		}
+		stack = append(stack, n)
+		switch node := n.(type) {
+		case *ast.ForStmt, *ast.RangeStmt:
+			loops++
+		case *ast.SendStmt:
+			if ident, ok := ast.Unparen(node.Chan).(*ast.Ident); ok && ident.Name == name {
+				sends++
+				inLoop = inLoop || loops > 0
+			}
+		}
+		return true
+	})
+	return sends == 1 && !inLoop
+}
+
+// applyFixes applies the fixes attached to issues, except for issues whose
+// fingerprint is in skip, and returns the issues that remain unfixed. Fixed
+// files are rewritten in place, or, if dryRun is set, left untouched with a
+// unified diff of the change written to w; nothing is fixed then, so every
+// issue is returned.
+func (a *Analyzer) applyFixes(issues []Issue, skip map[string]bool, dryRun bool, w io.Writer) ([]Issue, error) {
+	fixable := make(map[string]bool)
+	for _, rule := range rules {
+		fixable[rule.ID] = rule.Fixable
+	}
+
+	var remaining []Issue
+	edits := make(map[string][]*textEdit)
+	for _, issue := range issues {
+		if issue.fix == nil || !fixable[issue.Rule] || a.files[issue.fix.filename] == nil || skip[issue.Fingerprint()] {
+			remaining = append(remaining, issue)
+			continue
+		}
+		edits[issue.fix.filename] = append(edits[issue.fix.filename], issue.fix)
+		if dryRun {
+			remaining = append(remaining, issue)
+		}
+	}
+
+	filenames := make([]string, 0, len(edits))
+	for filename := range edits {
+		filenames = append(filenames, filename)
+	}
+	slices.Sort(filenames)
+
+	for _, filename := range filenames {
+		original := a.files[filename]
+		fixed, err := splice(original, edits[filename])
+		if err != nil {
+			return nil, fmt.Errorf("error fixing %s: %w", filename, err)
+		}
+
+		if dryRun {
+			if _, err := io.WriteString(w, unifiedDiff(filename, string(original), string(fixed))); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		info, err := os.Stat(filename)
+		if err != nil {
+			return nil, fmt.Errorf("error accessing %s: %w", filename, err)
+		}
+		if err := os.WriteFile(filename, fixed, info.Mode().Perm()); err != nil {
+			return nil, fmt.Errorf("error writing %s: %w", filename, err)
+		}
+	}
+	return remaining, nil
+}
+
+// splice returns src with edits applied. The edits may be in any order but
+// must not overlap.
+func splice(src []byte, edits []*textEdit) ([]byte, error) {
+	edits = slices.SortedFunc(slices.Values(edits), func(x, y *textEdit) int {
+		return cmp.Compare(x.start, y.start)
+	})
+
+	var out bytes.Buffer
+	last := 0
+	for _, edit := range edits {
+		if edit.start < last || edit.end < edit.start || edit.end > len(src) {
+			return nil, fmt.Errorf("overlapping or out of range fix at offset %d", edit.start)
+		}
+		out.Write(src[last:edit.start])
+		out.WriteString(edit.text)
+		last = edit.end
+	}
+	out.Write(src[last:])
+	return out.Bytes(), nil
+}
+
+// splitLines splits s into lines, keeping the line endings.
+func splitLines(s string) []string {
+	lines := strings.SplitAfter(s, "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+// diffContext is the number of unchanged lines shown around each change.
+const diffContext = 3
+
+// diffLine is a line of an edit script: op is ' ' for a line both sides
+// share, '-' for a removed line and '+' for an added one.
+type diffLine struct {
+	op   byte
+	line string
+}
+
+// diffLines appends to out the edit script turning a into b. It uses Myers'
+// linear-space refinement: after trimming the common prefix and suffix, it
+// finds the middle snake of a shortest edit script and recurses on either
+// side of it, so memory stays linear in the input however large the files.
+func diffLines(a, b []string, out []diffLine) []diffLine {
+	for len(a) > 0 && len(b) > 0 && a[0] == b[0] {
+		out = append(out, diffLine{' ', a[0]})
+		a, b = a[1:], b[1:]
+	}
+	common := 0
+	for common < len(a) && common < len(b) && a[len(a)-1-common] == b[len(b)-1-common] {
+		common++
+	}
+	suffix := a[len(a)-common:]
+	a, b = a[:len(a)-common], b[:len(b)-common]
+
+	switch {
+	case len(a) == 0:
+		for _, line := range b {
+			out = append(out, diffLine{'+', line})
+		}
+	case len(b) == 0:
+		for _, line := range a {
+			out = append(out, diffLine{'-', line})
+		}
+	default:
+		// With the ends trimmed, both sides differ at their first and last
+		// lines, so the script has at least two edits and each half of the
+		// split is strictly shorter.
+		x0, y0, x1, y1 := middleSnake(a, b)
+		out = diffLines(a[:x0], b[:y0], out)
+		for _, line := range a[x0:x1] {
+			out = append(out, diffLine{' ', line})
+		}
+		out = diffLines(a[x1:], b[y1:], out)
+	}
+
+	for _, line := range suffix {
+		out = append(out, diffLine{' ', line})
+	}
+	return out
+}
+
+// middleSnake returns the start (x0, y0) and end (x1, y1) of the snake in
+// the middle of a shortest edit script from a to b, searching forward from
+// the start and backward from the end until the paths overlap.
+func middleSnake(a, b []string) (x0, y0, x1, y1 int) {
+	n, m := len(a), len(b)
+	delta := n - m
+	odd := delta%2 != 0
+	limit := (n + m + 1) / 2
+
+	// forward[k] is the furthest x reached on diagonal k = x-y from the
+	// start; backward[k] is the furthest distance reached on diagonal k of
+	// the reversed sequences, which is diagonal delta-k going forward.
+	offset := limit + 1
+	forward := make([]int, 2*offset+1)
+	backward := make([]int, 2*offset+1)
+
+	for d := 0; d <= limit; d++ {
+		for k := -d; k <= d; k += 2 {
+			var x int
+			if k == -d || (k != d && forward[offset+k-1] < forward[offset+k+1]) {
+				x = forward[offset+k+1]
+			} else {
+				x = forward[offset+k-1] + 1
+			}
+			y := x - k
+			startX, startY := x, y
+			for x < n && y < m && a[x] == b[y] {
+				x++
+				y++
+			}
+			forward[offset+k] = x
+			if rk := delta - k; odd && rk >= -(d-1) && rk <= d-1 && x+backward[offset+rk] >= n {
+				return startX, startY, x, y
+			}
+		}
+
+		for k := -d; k <= d; k += 2 {
+			var x int
+			if k == -d || (k != d && backward[offset+k-1] < backward[offset+k+1]) {
+				x = backward[offset+k+1]
+			} else {
+				x = backward[offset+k-1] + 1
+			}
+			y := x - k
+			startX, startY := x, y
+			for x < n && y < m && a[n-1-x] == b[m-1-y] {
+				x++
+				y++
+			}
+			backward[offset+k] = x
+			if fk := delta - k; !odd && fk >= -d && fk <= d && x+forward[offset+fk] >= n {
+				return n - x, m - y, n - startX, m - startY
+			}
+		}
+	}
+	// Unreachable: a path of at most n+m edits always exists.
+	return 0, 0, n, m
+}
+
+// unifiedDiff returns a unified diff from before to after, or "" if they are
+// equal.
+func unifiedDiff(filename, before, after string) string {
+	if before == after {
+		return ""
+	}
+	edits := diffLines(splitLines(before), splitLines(after), nil)
+
+	var out strings.Builder
+	fmt.Fprintf(&out, "--- a/%s\n+++ b/%s\n", filename, filename)
+	oldLine, newLine := 1, 1
+	for k := 0; k < len(edits); {
+		if edits[k].op == ' ' {
+			oldLine++
+			newLine++
+			k++
+			continue
+		}
+
+		// Extend the hunk until diffContext*2 unchanged lines separate it
+		// from the next change.
+		start := max(k-diffContext, 0)
+		end := k
+		for end < len(edits) {
+			if edits[end].op != ' ' {
+				end++
+				continue
+			}
+			run := end
+			for run < len(edits) && edits[run].op == ' ' {
+				run++
+			}
+			if run == len(edits) || run-end > 2*diffContext {
+				end = min(end+diffContext, len(edits))
+				break
+			}
+			end = run
+		}
+
+		hunkOld, hunkNew := oldLine-(k-start), newLine-(k-start)
+		var oldCount, newCount int
+		var body strings.Builder
+		for _, e := range edits[start:end] {
+			if e.op != '+' {
+				oldCount++
+			}
+			if e.op != '-' {
+				newCount++
+			}
+			body.WriteByte(e.op)
+			body.WriteString(e.line)
+			if !strings.HasSuffix(e.line, "\n") {
+				body.WriteString("\n\\ No newline at end of file\n")
+			}
+		}
+		fmt.Fprintf(&out, "@@ -%d,%d +%d,%d @@\n", hunkOld, oldCount, hunkNew, newCount)
+		out.WriteString(body.String())
+
+		for _, e := range edits[k:end] {
+			if e.op != '+' {
+				oldLine++
+			}
+			if e.op != '-' {
+				newLine++
+			}
+		}
+		k = end
+	}
+	return out.String()
+}
@@ -0,0 +1,303 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"os"
+	"strings"
+	"testing"
+)
+
+const fixInput = `package test
+
+// result computes a value in the background.
+func result() int {
+	ch := make(chan int)
+	go func() {
+		ch <- 42
+	}()
+	return <-ch
+}
+
+func stream(n int) chan int {
+	out := make(chan int)
+	go func() {
+		for i := 0; i < n; i++ {
+			out <- i
+		}
+		close(out)
+	}()
+	return out
+}
+`
+
+const fixExpected = `package test
+
+// result computes a value in the background.
+func result() int {
+	ch := make(chan int, 1)
+	go func() {
+		ch <- 42
+	}()
+	return <-ch
+}
+
+func stream(n int) chan int {
+	out := make(chan int)
+	go func() {
+		for i := 0; i < n; i++ {
+			out <- i
+		}
+		close(out)
+	}()
+	return out
+}
+`
+
+func TestApplyFixes(t *testing.T) {
+	path := writeFile(t, t.TempDir(), "result.go", fixInput)
+
+	analyzer := &Analyzer{
+		fset: token.NewFileSet(),
+		fix:  true,
+	}
+	if err := analyzer.analyzeFile(context.Background(), path); err != nil {
+		t.Fatalf("analysis failed: %v", err)
+	}
+
+	remaining, err := analyzer.applyFixes(analyzer.issues, nil, false, &bytes.Buffer{})
+	if err != nil {
+		t.Fatalf("applyFixes failed: %v", err)
+	}
+	if got := countMessages(remaining, "unbuffered channel creation"); got != 1 {
+		t.Errorf("got %d unfixed unbuffered channels, want only the streaming one: %s", got, formatIssues(remaining))
+	}
+
+	fixed, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read fixed file: %v", err)
+	}
+	if string(fixed) != fixExpected {
+		t.Errorf("unexpected fixed source:\n%s", fixed)
+	}
+
+	// The rewritten source must still type-check.
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, path, fixed, 0)
+	if err != nil {
+		t.Fatalf("fixed source does not parse: %v", err)
+	}
+	conf := types.Config{Importer: sharedImporter}
+	if _, err := conf.Check("test", fset, []*ast.File{file}, nil); err != nil {
+		t.Errorf("fixed source does not compile: %v", err)
+	}
+}
+
+func TestApplyFixes_DryRun(t *testing.T) {
+	path := writeFile(t, t.TempDir(), "result.go", fixInput)
+
+	var stdout, stderr bytes.Buffer
+	if _, err := run([]string{"-path", path, "-fix", "-dry-run", "-output", "json"}, &stdout, &stderr); err != nil {
+		t.Fatalf("run failed: %v", err)
+	}
+
+	unchanged, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read file: %v", err)
+	}
+	if string(unchanged) != fixInput {
+		t.Errorf("dry run modified the file")
+	}
+
+	var output JSONOutput
+	if err := json.Unmarshal(stdout.Bytes(), &output); err != nil {
+		t.Errorf("stdout is not the JSON report: %v\n%s", err, stdout.String())
+	}
+	// Nothing was fixed, so the issue the diff resolves is still reported.
+	unfixed := 0
+	for _, issue := range output.Issues {
+		if issue.Rule == RuleUnbufferedChannel && issue.Position.StartLine == 5 {
+			unfixed++
+		}
+	}
+	if unfixed != 1 {
+		t.Errorf("got %d reports of the previewed unbuffered channel, want 1: %+v", unfixed, output.Issues)
+	}
+
+	for _, line := range []string{
+		"--- a/" + path,
+		"+++ b/" + path,
+		"@@ -2,7 +2,7 @@",
+		"-\tch := make(chan int)\n",
+		"+\tch := make(chan int, 1)\n",
+	} {
+		if !strings.Contains(stderr.String(), line) {
+			t.Errorf("diff missing %q:\n%s", line, stderr.String())
+		}
+	}
+}
+
+func TestApplyFixes_KeepsFormatting(t *testing.T) {
+	// gofmt would realign the comments and drop the trailing comma's line
+	// break; a fix must only touch the make call.
+	const input = `package test
+
+func result() int {
+	ch := make(chan int,
+	)
+	x  :=  1 // not gofmt'd
+	go func() {
+		ch <- x
+	}()
+	return <-ch
+}
+`
+	const expected = `package test
+
+func result() int {
+	ch := make(chan int, 1,
+	)
+	x  :=  1 // not gofmt'd
+	go func() {
+		ch <- x
+	}()
+	return <-ch
+}
+`
+	path := writeFile(t, t.TempDir(), "result.go", input)
+	if _, err := run([]string{"-path", path, "-fix"}, &bytes.Buffer{}, &bytes.Buffer{}); err != nil {
+		t.Fatalf("run failed: %v", err)
+	}
+	fixed, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read fixed file: %v", err)
+	}
+	if string(fixed) != expected {
+		t.Errorf("unexpected fixed source:\n%s", fixed)
+	}
+}
+
+func TestApplyFixes_SkipsSuppressed(t *testing.T) {
+	dir := t.TempDir()
+	path := writeFile(t, dir, "result.go", fixInput)
+
+	var stdout bytes.Buffer
+	if _, err := run([]string{"-path", path, "-output", "json"}, &stdout, &bytes.Buffer{}); err != nil {
+		t.Fatalf("run failed: %v", err)
+	}
+	var output JSONOutput
+	if err := json.Unmarshal(stdout.Bytes(), &output); err != nil {
+		t.Fatalf("failed to parse output: %v", err)
+	}
+	var fingerprints []string
+	for _, issue := range output.Issues {
+		if issue.Rule == RuleUnbufferedChannel && issue.Position.StartLine == 5 {
+			fingerprints = append(fingerprints, issue.Fingerprint)
+		}
+	}
+	if len(fingerprints) != 1 {
+		t.Fatalf("got %d fixable issues, want 1: %+v", len(fingerprints), output.Issues)
+	}
+	list := writeFile(t, dir, "list.txt", fingerprints[0]+"\n")
+
+	for _, flag := range []string{"-ignore-file", "-baseline"} {
+		t.Run(flag, func(t *testing.T) {
+			if _, err := run([]string{"-path", path, "-fix", flag, list}, &bytes.Buffer{}, &bytes.Buffer{}); err != nil {
+				t.Fatalf("run failed: %v", err)
+			}
+			source, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatalf("failed to read file: %v", err)
+			}
+			if string(source) != fixInput {
+				t.Errorf("suppressed issue was fixed:\n%s", source)
+			}
+		})
+	}
+}
+
+func TestUnifiedDiff(t *testing.T) {
+	if got := unifiedDiff("same.go", "a\nb\n", "a\nb\n"); got != "" {
+		t.Errorf("expected no diff for equal input, got %q", got)
+	}
+
+	before := "1\n2\n3\n4\n5\n6\n7\n8\n9\n10\n11\n12\n"
+	after := "1\nTWO\n3\n4\n5\n6\n7\n8\n9\n10\n11\nTWELVE\n"
+	want := `--- a/x.go
++++ b/x.go
+@@ -1,5 +1,5 @@
+ 1
+-2
++TWO
+ 3
+ 4
+ 5
+@@ -9,4 +9,4 @@
+ 9
+ 10
+ 11
+-12
++TWELVE
+`
+	if got := unifiedDiff("x.go", before, after); got != want {
+		t.Errorf("got diff:\n%s\nwant:\n%s", got, want)
+	}
+
+	// Large inputs must not need memory quadratic in their length.
+	var large, changed strings.Builder
+	for i := range 20000 {
+		fmt.Fprintf(&large, "line %d\n", i)
+		if i%5000 == 0 {
+			fmt.Fprintf(&changed, "changed %d\n", i)
+		} else {
+			fmt.Fprintf(&changed, "line %d\n", i)
+		}
+	}
+	got := unifiedDiff("large.go", large.String(), changed.String())
+	if hunks := strings.Count(got, "@@ -"); hunks != 4 {
+		t.Errorf("got %d hunks, want 4:\n%s", hunks, got)
+	}
+	if !strings.Contains(got, "-line 15000\n+changed 15000\n") {
+		t.Errorf("diff missing the change at line 15000:\n%s", got)
+	}
+}
+
+func TestDiffLines(t *testing.T) {
+	// Applying the edit script to a must give b, and its unchanged lines
+	// must be a longest common subsequence.
+	tests := []struct {
+		a, b string
+		lcs  int
+	}{
+		{"abcabba", "cbabac", 4},
+		{"abc", "xyz", 0},
+		{"", "abc", 0},
+		{"abcdef", "abXdeYf", 5},
+		{"aaaa", "aa", 2},
+	}
+	for _, tt := range tests {
+		edits := diffLines(strings.Split(tt.a, ""), strings.Split(tt.b, ""), nil)
+		var before, after strings.Builder
+		common := 0
+		for _, e := range edits {
+			if e.op != '+' {
+				before.WriteString(e.line)
+			}
+			if e.op != '-' {
+				after.WriteString(e.line)
+			}
+			if e.op == ' ' {
+				common++
+			}
+		}
+		if before.String() != tt.a || after.String() != tt.b || common != tt.lcs {
+			t.Errorf("diffLines(%q, %q) = %q -> %q with %d common, want %d", tt.a, tt.b, before.String(), after.String(), common, tt.lcs)
+		}
+	}
+}
@@ -0,0 +1,103 @@
+package main
+
+import (
+	"context"
+	"go/parser"
+	"go/token"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// wantPattern matches each expectation in a `// channelcheck: want "..."`
+// comment. A comment may list several quoted expectations.
+var wantPattern = regexp.MustCompile(`"((?:[^"\\]|\\.)*)"`)
+
+// fixtureWant is an expected issue in a fixture file.
+type fixtureWant struct {
+	line    int
+	message string
+	matched bool
+}
+
+// parseWants returns the expectations declared in the fixture at path.
+func parseWants(t *testing.T, path string) []*fixtureWant {
+	t.Helper()
+
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, path, nil, parser.ParseComments)
+	if err != nil {
+		t.Fatalf("failed to parse fixture: %v", err)
+	}
+
+	var wants []*fixtureWant
+	for _, group := range file.Comments {
+		for _, comment := range group.List {
+			text := strings.TrimSpace(strings.TrimPrefix(comment.Text, "//"))
+			rest, ok := strings.CutPrefix(text, "channelcheck: want ")
+			if !ok {
+				continue
+			}
+			line := fset.Position(comment.Pos()).Line
+			for _, match := range wantPattern.FindAllString(rest, -1) {
+				message, err := strconv.Unquote(match)
+				if err != nil {
+					t.Fatalf("%s:%d: invalid want %s: %v", path, line, match, err)
+				}
+				wants = append(wants, &fixtureWant{line: line, message: message})
+			}
+		}
+	}
+	return wants
+}
+
+// TestFixtures analyzes every file in testdata/fixtures and checks the issues
+// against its `// channelcheck: want "message substring"` comments. Each want
+// must be matched by an issue on the same line, and every issue must be
+// wanted.
+func TestFixtures(t *testing.T) {
+	paths, err := filepath.Glob(filepath.Join("testdata", "fixtures", "*.go"))
+	if err != nil {
+		t.Fatalf("failed to list fixtures: %v", err)
+	}
+	if len(paths) == 0 {
+		t.Fatalf("no fixtures found")
+	}
+
+	for _, path := range paths {
+		t.Run(filepath.Base(path), func(t *testing.T) {
+			analyzer := &Analyzer{
+				fset: token.NewFileSet(),
+			}
+			if err := analyzer.analyzeFile(context.Background(), path); err != nil {
+				t.Fatalf("failed to analyze fixture: %v", err)
+			}
+
+			wants := parseWants(t, path)
+			for _, issue := range analyzer.issues {
+				if !matchWant(wants, issue) {
+					t.Errorf("%s:%d: unexpected issue: [%s] %s", path, issue.Pos.StartLine, issue.Severity, issue.Message)
+				}
+			}
+			for _, want := range wants {
+				if !want.matched {
+					t.Errorf("%s:%d: no issue matching %q", path, want.line, want.message)
+				}
+			}
+		})
+	}
+}
+
+// matchWant marks the first unmatched want on the issue's line whose message
+// is contained in the issue message, and reports whether one was found.
+func matchWant(wants []*fixtureWant, issue Issue) bool {
+	for _, want := range wants {
+		if !want.matched && want.line == issue.Pos.StartLine && strings.Contains(issue.Message, want.message) {
+			want.matched = true
+			return true
+		}
+	}
+	return false
+}
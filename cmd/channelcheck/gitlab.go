@@ -0,0 +1,67 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+)
+
+// GitLab Code Quality report types. The report is a JSON array of these
+// issues; see https://docs.gitlab.com/ci/testing/code_quality/.
+type (
+	gitlabIssue struct {
+		Description string         `json:"description"`
+		CheckName   string         `json:"check_name"`
+		Fingerprint string         `json:"fingerprint"`
+		Severity    string         `json:"severity"`
+		Location    gitlabLocation `json:"location"`
+	}
+
+	gitlabLocation struct {
+		Path  string      `json:"path"`
+		Lines gitlabLines `json:"lines"`
+	}
+
+	gitlabLines struct {
+		Begin int `json:"begin"`
+	}
+)
+
+// gitlabSeverity maps a severity to a GitLab Code Quality severity.
+func gitlabSeverity(severity string) string {
+	switch severity {
+	case "ERROR":
+		return "critical"
+	case "WARNING":
+		return "major"
+	default:
+		return "info"
+	}
+}
+
+// printGitLab writes the issues as a GitLab Code Quality report, for the
+// codequality artifact of a CI job.
+func printGitLab(w io.Writer, issues []Issue) error {
+	report := make([]gitlabIssue, len(issues))
+	for i, issue := range issues {
+		report[i] = gitlabIssue{
+			Description: issue.Message,
+			CheckName:   issue.Rule,
+			Fingerprint: issue.Fingerprint(),
+			Severity:    gitlabSeverity(issue.Severity),
+			Location: gitlabLocation{
+				Path:  filepath.ToSlash(issue.Pos.Filename),
+				Lines: gitlabLines{Begin: issue.Pos.StartLine},
+			},
+		}
+	}
+
+	jsonBytes, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return fmt.Errorf("error marshaling GitLab report: %w", err)
+	}
+
+	_, err = fmt.Fprintln(w, string(jsonBytes))
+	return err
+}
@@ -0,0 +1,45 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+)
+
+func TestPrintGitLab(t *testing.T) {
+	var buf bytes.Buffer
+	if err := printOutput(&buf, OutputFormatGitLab, sampleIssues); err != nil {
+		t.Fatalf("printOutput failed: %v", err)
+	}
+
+	var report []gitlabIssue
+	if err := json.Unmarshal(buf.Bytes(), &report); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if len(report) != len(sampleIssues) {
+		t.Fatalf("got %d issues, want %d", len(report), len(sampleIssues))
+	}
+	for i, issue := range sampleIssues {
+		got := report[i]
+		if got.Description == "" || got.Fingerprint == "" || got.Location.Path == "" || got.Location.Lines.Begin == 0 {
+			t.Errorf("issue %d is missing required fields: %+v", i, got)
+		}
+		if got.Fingerprint != issue.Fingerprint() {
+			t.Errorf("issue %d: got fingerprint %q, want %q", i, got.Fingerprint, issue.Fingerprint())
+		}
+		if got.CheckName != issue.Rule || got.Location.Path != issue.Pos.Filename || got.Location.Lines.Begin != issue.Pos.StartLine {
+			t.Errorf("issue %d: got %+v for %+v", i, got, issue)
+		}
+	}
+	if report[0].Severity != "major" || report[1].Severity != "info" {
+		t.Errorf("got severities %q and %q, want major and info", report[0].Severity, report[1].Severity)
+	}
+
+	buf.Reset()
+	if err := printGitLab(&buf, nil); err != nil {
+		t.Fatalf("printGitLab failed: %v", err)
+	}
+	if got := buf.String(); got != "[]\n" {
+		t.Errorf("got %q for no issues, want an empty array", got)
+	}
+}
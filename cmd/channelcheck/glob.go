@@ -0,0 +1,91 @@
+package main
+
+import (
+	"fmt"
+	"io/fs"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// hasGlobMeta reports whether p contains glob metacharacters.
+func hasGlobMeta(p string) bool {
+	return strings.ContainsAny(p, "*?[")
+}
+
+// expandGlob returns the Go files matching pattern. Besides the syntax of
+// path.Match, a `**` segment matches zero or more directories, so
+// `internal/**/*.go` matches every Go file below internal.
+func expandGlob(pattern string) ([]string, error) {
+	segments := strings.Split(filepath.ToSlash(pattern), "/")
+
+	// Walk from the longest leading directory without metacharacters.
+	literal := 0
+	for literal < len(segments)-1 && !hasGlobMeta(segments[literal]) {
+		literal++
+	}
+	base := strings.Join(segments[:literal], "/")
+	switch {
+	case literal == 0:
+		base = "."
+	case base == "":
+		base = "/"
+	}
+	rest := segments[literal:]
+
+	for _, segment := range rest {
+		if segment == "**" {
+			continue
+		}
+		if _, err := path.Match(segment, ""); err != nil {
+			return nil, fmt.Errorf("invalid glob pattern %q: %w", pattern, err)
+		}
+	}
+
+	var matches []string
+	err := filepath.WalkDir(filepath.FromSlash(base), func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || !strings.HasSuffix(p, ".go") {
+			return nil
+		}
+		rel, err := filepath.Rel(filepath.FromSlash(base), p)
+		if err != nil {
+			return err
+		}
+		if matchSegments(rest, strings.Split(filepath.ToSlash(rel), "/")) {
+			matches = append(matches, p)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error expanding %s: %w", pattern, err)
+	}
+	if len(matches) == 0 {
+		return nil, fmt.Errorf("no Go files match %s", pattern)
+	}
+	return matches, nil
+}
+
+// matchSegments matches path segments against pattern segments, where a
+// `**` pattern segment matches any number of path segments.
+func matchSegments(pattern, segments []string) bool {
+	if len(pattern) == 0 {
+		return len(segments) == 0
+	}
+	if pattern[0] == "**" {
+		for i := 0; i <= len(segments); i++ {
+			if matchSegments(pattern[1:], segments[i:]) {
+				return true
+			}
+		}
+		return false
+	}
+	if len(segments) == 0 {
+		return false
+	}
+	// The pattern was validated up front, so Match cannot fail here.
+	ok, _ := path.Match(pattern[0], segments[0])
+	return ok && matchSegments(pattern[1:], segments[1:])
+}
@@ -0,0 +1,93 @@
+package main
+
+import (
+	"context"
+	"go/token"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestExpandGlob(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{
+		"main.go",
+		"notes.txt",
+		"internal/a.go",
+		"internal/a_test.go",
+		"internal/deep/nested/b.go",
+		"other/c.go",
+	} {
+		writeFile(t, dir, name, "package p\n")
+	}
+
+	tests := []struct {
+		name     string
+		pattern  string
+		expected []string
+	}{
+		{
+			name:     "single level",
+			pattern:  "internal/*.go",
+			expected: []string{"internal/a.go", "internal/a_test.go"},
+		},
+		{
+			name:     "recursive",
+			pattern:  "internal/**/*.go",
+			expected: []string{"internal/a.go", "internal/a_test.go", "internal/deep/nested/b.go"},
+		},
+		{
+			name:     "recursive with name filter",
+			pattern:  "**/*_test.go",
+			expected: []string{"internal/a_test.go"},
+		},
+		{
+			name:     "top-level files only",
+			pattern:  "*.go",
+			expected: []string{"main.go"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			files, err := expandGlob(filepath.Join(dir, tt.pattern))
+			if err != nil {
+				t.Fatalf("expandGlob failed: %v", err)
+			}
+			var got []string
+			for _, file := range files {
+				rel, err := filepath.Rel(dir, file)
+				if err != nil {
+					t.Fatalf("unexpected path %s: %v", file, err)
+				}
+				got = append(got, filepath.ToSlash(rel))
+			}
+			if !slices.Equal(got, tt.expected) {
+				t.Errorf("got %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestExpandGlob_NoMatches(t *testing.T) {
+	if _, err := expandGlob(filepath.Join(t.TempDir(), "**", "*.go")); err == nil {
+		t.Errorf("expected an error when nothing matches")
+	}
+}
+
+func TestAnalyzePath_Glob(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "pkg/a.go", "package pkg\nfunc f(ch chan int) { ch <- 1 }\n")
+	writeFile(t, dir, "pkg/sub/b.go", "package sub\nfunc g(ch chan int) { ch <- 1 }\n")
+	writeFile(t, dir, "skip/c.go", "package skip\nfunc h(ch chan int) { ch <- 1 }\n")
+
+	analyzer := &Analyzer{
+		fset: token.NewFileSet(),
+	}
+	if err := analyzer.analyzePath(context.Background(), filepath.Join(dir, "pkg", "**", "*.go")); err != nil {
+		t.Fatalf("analysis failed: %v", err)
+	}
+	if len(analyzer.issues) != 2 {
+		t.Errorf("got %d issues, want 2: %s", len(analyzer.issues), formatIssues(analyzer.issues))
+	}
+}
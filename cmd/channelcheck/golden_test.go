@@ -0,0 +1,44 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// record rewrites the golden files from the current output instead of
+// comparing against them: go test -run TestGolden -record.
+var record = flag.Bool("record", false, "rewrite testdata/golden output files with the current output")
+
+// TestGolden renders testdata/golden/worker.go in every output format and
+// compares the result with testdata/golden/worker.<format>.golden, so
+// formatter changes show up as a diff of the golden files.
+func TestGolden(t *testing.T) {
+	input := filepath.Join("testdata", "golden", "worker.go")
+	for _, format := range outputFormats {
+		t.Run(string(format), func(t *testing.T) {
+			var stdout bytes.Buffer
+			if _, err := run([]string{"-path", input, "-output", string(format)}, &stdout, &bytes.Buffer{}); err != nil {
+				t.Fatalf("run failed: %v", err)
+			}
+
+			golden := filepath.Join("testdata", "golden", "worker."+string(format)+".golden")
+			if *record {
+				if err := os.WriteFile(golden, stdout.Bytes(), 0o644); err != nil {
+					t.Fatalf("failed to record golden file: %v", err)
+				}
+				return
+			}
+
+			want, err := os.ReadFile(golden)
+			if err != nil {
+				t.Fatalf("failed to read golden file (record it with -record): %v", err)
+			}
+			if got := stdout.String(); got != string(want) {
+				t.Errorf("output drifted from %s (rerun with -record if intended):\ngot:\n%s\nwant:\n%s", golden, got, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"go/version"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+// loopVarSemantics is the Go version from which each loop iteration has its
+// own copy of the loop variables.
+const loopVarSemantics = "go1.22"
+
+// parseGoVersion parses a -go value such as 1.21, 1.21.3 or go1.21 into the
+// language version it selects, such as go1.21.
+func parseGoVersion(s string) (string, error) {
+	v := "go" + strings.TrimPrefix(s, "go")
+	if !version.IsValid(v) {
+		return "", fmt.Errorf("invalid -go value %q: want a Go version such as 1.21", s)
+	}
+	return version.Lang(v), nil
+}
+
+// defaultGoVersion returns the language version to analyze path with: the
+// go directive of the module containing path if there is one, otherwise the
+// version of the toolchain channelcheck was built with.
+func defaultGoVersion(path string) string {
+	if abs, err := filepath.Abs(path); err == nil {
+		dir := abs
+		if info, err := os.Stat(abs); err != nil || !info.IsDir() {
+			dir = filepath.Dir(abs)
+		}
+		if root := make(moduleRoots).find(dir); root != "" {
+			if v := goDirective(filepath.Join(root, "go.mod")); v != "" {
+				return v
+			}
+		}
+	}
+	return version.Lang(runtime.Version())
+}
+
+// goDirective returns the language version in the go directive of the
+// go.mod file at path, or "" if it cannot be read.
+func goDirective(path string) string {
+	f, err := os.Open(path)
+	if err != nil {
+		return ""
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 2 && fields[0] == "go" {
+			if v, err := parseGoVersion(fields[1]); err == nil {
+				return v
+			}
+		}
+	}
+	return ""
+}
+
+// goBefore reports whether the analyzed code targets a Go version older than
+// v. An unknown version is treated as current, so version-specific checks
+// for old releases stay off.
+func (a *Analyzer) goBefore(v string) bool {
+	return a.goVersion != "" && version.Compare(a.goVersion, v) < 0
+}
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestParseGoVersion(t *testing.T) {
+	for input, want := range map[string]string{
+		"1.21":     "go1.21",
+		"1.21.3":   "go1.21",
+		"go1.22":   "go1.22",
+		"1.22rc1":  "go1.22",
+		"go1.24.0": "go1.24",
+	} {
+		if got, err := parseGoVersion(input); err != nil || got != want {
+			t.Errorf("parseGoVersion(%q) = %q, %v; want %q", input, got, err, want)
+		}
+	}
+	for _, input := range []string{"", "latest", "v1.21"} {
+		if _, err := parseGoVersion(input); err == nil {
+			t.Errorf("parseGoVersion(%q): expected an error", input)
+		}
+	}
+}
+
+func TestRun_GoVersion(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "go.mod", "module example.com/legacy\n\ngo 1.21\n")
+	writeFile(t, dir, "worker.go", `package legacy
+func run(jobs []int, results chan int) {
+	for _, job := range jobs {
+		go func() {
+			select {
+			case results <- job:
+			default:
+			}
+		}()
+	}
+}
+`)
+
+	tests := []struct {
+		name string
+		args []string
+		want bool
+	}{
+		{name: "module go directive", args: nil, want: true},
+		{name: "explicit older version", args: []string{"-go", "1.21"}, want: true},
+		{name: "explicit newer version", args: []string{"-go", "1.22"}, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var stdout bytes.Buffer
+			args := append([]string{"-path", dir}, tt.args...)
+			if _, err := run(args, &stdout, &bytes.Buffer{}); err != nil {
+				t.Fatalf("run failed: %v", err)
+			}
+			if got := strings.Contains(stdout.String(), "captures loop variable job"); got != tt.want {
+				t.Errorf("loop-var-capture reported = %v, want %v:\n%s", got, tt.want, stdout.String())
+			}
+		})
+	}
+
+	if _, err := run([]string{"-path", dir, "-go", "latest"}, &bytes.Buffer{}, &bytes.Buffer{}); err == nil {
+		t.Errorf("expected an error for an invalid -go value")
+	}
+}
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// loadIgnoreFile reads the fingerprints listed in an -ignore-file, one per
+// line. Blank lines and lines starting with # are skipped, and anything
+// after the fingerprint on a line is treated as a comment.
+func loadIgnoreFile(path string) (map[string]bool, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading ignore file: %w", err)
+	}
+	defer f.Close()
+
+	ignored, err := readFingerprints(f)
+	if err != nil {
+		return nil, fmt.Errorf("error reading ignore file: %w", err)
+	}
+	return ignored, nil
+}
+
+// readFingerprints reads one fingerprint per line, in the format described
+// by loadIgnoreFile.
+func readFingerprints(r io.Reader) (map[string]bool, error) {
+	fingerprints := make(map[string]bool)
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 || strings.HasPrefix(fields[0], "#") {
+			continue
+		}
+		fingerprints[fields[0]] = true
+	}
+	return fingerprints, scanner.Err()
+}
+
+// filterIgnored drops the issues whose fingerprints are in ignored.
+func filterIgnored(issues []Issue, ignored map[string]bool) []Issue {
+	var kept []Issue
+	for _, issue := range issues {
+		if !ignored[issue.Fingerprint()] {
+			kept = append(kept, issue)
+		}
+	}
+	return kept
+}
@@ -0,0 +1,56 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"path/filepath"
+	"testing"
+)
+
+func TestRun_IgnoreFile(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "a.go", `package test
+func send() {
+	ch := make(chan int)
+	ch <- 1
+}
+`)
+
+	report := func(args ...string) JSONOutput {
+		t.Helper()
+		var stdout bytes.Buffer
+		args = append([]string{"-path", dir, "-output", "json"}, args...)
+		if _, err := run(args, &stdout, &bytes.Buffer{}); err != nil {
+			t.Fatalf("run failed: %v", err)
+		}
+		var output JSONOutput
+		if err := json.Unmarshal(stdout.Bytes(), &output); err != nil {
+			t.Fatalf("failed to parse output: %v", err)
+		}
+		return output
+	}
+
+	all := report()
+	if all.Total != 2 {
+		t.Fatalf("got %d issues, want 2", all.Total)
+	}
+	suppressed, kept := all.Issues[0], all.Issues[1]
+
+	ignoreFile := writeFile(t, t.TempDir(), "ignore.txt", "# accepted findings\n\n"+
+		suppressed.Fingerprint+" "+suppressed.Message+"\n")
+	output := report("-ignore-file", ignoreFile)
+	if output.Total != 1 || output.Issues[0].Fingerprint != kept.Fingerprint {
+		t.Errorf("got %+v, want only %+v", output.Issues, kept)
+	}
+
+	// Raising the severity must not change which findings the file matches.
+	output = report("-ignore-file", ignoreFile, "-warn-as-error", suppressed.Rule)
+	if output.Total != 1 || output.Issues[0].Fingerprint != kept.Fingerprint {
+		t.Errorf("with -warn-as-error got %+v, want only %+v", output.Issues, kept)
+	}
+
+	missing := filepath.Join(t.TempDir(), "missing.txt")
+	if _, err := run([]string{"-path", dir, "-ignore-file", missing}, &bytes.Buffer{}, &bytes.Buffer{}); err == nil {
+		t.Errorf("expected an error for a missing ignore file")
+	}
+}
@@ -0,0 +1,101 @@
+package main
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"path/filepath"
+)
+
+// Values of -junit-grouping.
+const (
+	// junitByFile reports one testcase per file, with a failure for each
+	// of its issues.
+	junitByFile = "file"
+	// junitByIssue reports one testcase per issue.
+	junitByIssue = "issue"
+)
+
+// JUnit XML report types, in the subset understood by common CI test
+// report viewers.
+type (
+	junitTestSuites struct {
+		XMLName  xml.Name         `xml:"testsuites"`
+		Name     string           `xml:"name,attr"`
+		Tests    int              `xml:"tests,attr"`
+		Failures int              `xml:"failures,attr"`
+		Suites   []junitTestSuite `xml:"testsuite"`
+	}
+
+	junitTestSuite struct {
+		Name      string          `xml:"name,attr"`
+		Tests     int             `xml:"tests,attr"`
+		Failures  int             `xml:"failures,attr"`
+		TestCases []junitTestCase `xml:"testcase"`
+	}
+
+	junitTestCase struct {
+		Name      string         `xml:"name,attr"`
+		ClassName string         `xml:"classname,attr"`
+		Failures  []junitFailure `xml:"failure"`
+	}
+
+	junitFailure struct {
+		Message string `xml:"message,attr"`
+		Type    string `xml:"type,attr"`
+		Text    string `xml:",chardata"`
+	}
+)
+
+// printJUnit writes the issues as a JUnit XML report, grouped into
+// testcases per file or per issue as grouping, a -junit-grouping value,
+// selects.
+func printJUnit(w io.Writer, issues []Issue, grouping string) error {
+	var cases []junitTestCase
+	byFile := make(map[string]int) // filename -> index in cases
+	for _, issue := range issues {
+		filename := filepath.ToSlash(issue.Pos.Filename)
+		failure := junitFailure{
+			Message: issue.Message,
+			Type:    issue.Severity,
+			Text:    fmt.Sprintf("[%s] %s: %s (%s)", issue.Severity, issue.Pos, issue.Message, issue.Rule),
+		}
+
+		if grouping == junitByIssue {
+			cases = append(cases, junitTestCase{
+				Name:      fmt.Sprintf("%s %s:%d:%d", issue.Rule, filename, issue.Pos.StartLine, issue.Pos.StartColumn),
+				ClassName: filename,
+				Failures:  []junitFailure{failure},
+			})
+			continue
+		}
+		i, ok := byFile[filename]
+		if !ok {
+			i = len(cases)
+			byFile[filename] = i
+			cases = append(cases, junitTestCase{Name: filename, ClassName: "channelcheck"})
+		}
+		cases[i].Failures = append(cases[i].Failures, failure)
+	}
+
+	suite := junitTestSuite{
+		Name:      "channelcheck",
+		Tests:     len(cases),
+		Failures:  len(cases),
+		TestCases: cases,
+	}
+	report := junitTestSuites{
+		Name:     "channelcheck",
+		Tests:    suite.Tests,
+		Failures: suite.Failures,
+		Suites:   []junitTestSuite{suite},
+	}
+
+	xmlBytes, err := xml.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return fmt.Errorf("error marshaling JUnit report: %w", err)
+	}
+
+	_, err = fmt.Fprintf(w, "%s%s\n", xml.Header, xmlBytes)
+	return err
+}
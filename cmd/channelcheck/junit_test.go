@@ -0,0 +1,66 @@
+package main
+
+import (
+	"bytes"
+	"encoding/xml"
+	"testing"
+)
+
+func TestPrintJUnit(t *testing.T) {
+	issues := append([]Issue{{
+		Rule:     RuleBusyRetry,
+		Pos:      Position{Filename: "pkg/client.go", StartLine: 3, StartColumn: 1, EndLine: 3, EndColumn: 5},
+		Message:  "busy retry",
+		Severity: "WARNING",
+	}}, sampleIssues...)
+
+	tests := []struct {
+		grouping string
+		cases    int
+		failures []int // per testcase
+	}{
+		{grouping: junitByFile, cases: 2, failures: []int{1, 2}},
+		{grouping: junitByIssue, cases: 3, failures: []int{1, 1, 1}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.grouping, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := printJUnit(&buf, issues, tt.grouping); err != nil {
+				t.Fatalf("printJUnit failed: %v", err)
+			}
+
+			var report junitTestSuites
+			if err := xml.Unmarshal(buf.Bytes(), &report); err != nil {
+				t.Fatalf("invalid XML: %v\n%s", err, buf.String())
+			}
+			if len(report.Suites) != 1 {
+				t.Fatalf("got %d test suites, want 1", len(report.Suites))
+			}
+			suite := report.Suites[0]
+			if len(suite.TestCases) != tt.cases || suite.Tests != tt.cases || report.Tests != tt.cases {
+				t.Fatalf("got %d testcases (tests=%d), want %d:\n%s", len(suite.TestCases), suite.Tests, tt.cases, buf.String())
+			}
+			for i, tc := range suite.TestCases {
+				if len(tc.Failures) != tt.failures[i] {
+					t.Errorf("testcase %s: got %d failures, want %d", tc.Name, len(tc.Failures), tt.failures[i])
+				}
+			}
+		})
+	}
+
+	var buf bytes.Buffer
+	if err := printJUnit(&buf, nil, junitByFile); err != nil {
+		t.Fatalf("printJUnit failed: %v", err)
+	}
+	var report junitTestSuites
+	if err := xml.Unmarshal(buf.Bytes(), &report); err != nil || report.Tests != 0 {
+		t.Errorf("empty report: got %+v, %v", report, err)
+	}
+}
+
+func TestRun_JUnitGrouping(t *testing.T) {
+	if _, err := run([]string{"-path", ".", "-output", "junit", "-junit-grouping", "package"}, &bytes.Buffer{}, &bytes.Buffer{}); err == nil {
+		t.Errorf("expected an error for an unknown -junit-grouping")
+	}
+}
@@ -1,16 +1,27 @@
 package main
 
 import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"flag"
 	"fmt"
 	"go/ast"
 	"go/parser"
+	"go/scanner"
 	"go/token"
+	"go/types"
+	"io"
 	"log"
 	"os"
+	"path"
 	"path/filepath"
+	"slices"
 	"strings"
+	"sync"
+	"time"
 )
 
 // Position represents a range in the source code
@@ -30,15 +41,124 @@ func (p Position) String() string {
 }
 
 type Issue struct {
+	Rule     string
 	Pos      Position
 	Message  string
 	Severity string
+	// Func is the function containing the issue, as Name or Type.Method,
+	// when the check records it.
+	Func string
+	// Package is the name in the package clause of the issue's file.
+	Package string
+
+	// fix is the edit to the issue's file that resolves it. It is only set
+	// when the analyzer runs with fixes enabled.
+	fix *textEdit
+}
+
+// Fingerprint identifies an issue across runs. It covers the file, line,
+// rule and message, so the same finding in an unchanged file keeps its
+// fingerprint, even when -preset, -warn-as-error or a config changes its
+// severity.
+func (i Issue) Fingerprint() string {
+	h := sha256.New()
+	fmt.Fprintf(h, "%s\x00%d\x00%s\x00%s", i.Pos.Filename, i.Pos.StartLine, i.Rule, i.Message)
+	return hex.EncodeToString(h.Sum(nil)[:8])
 }
 
 type Analyzer struct {
+	// mu guards issues, filesAnalyzed and files, so checks running on other
+	// goroutines can report through addIssue and files analyzed at the same
+	// time can merge their results. The per-file state below, such as stack
+	// and info, belongs to the file being analyzed: analyzeSource gives each
+	// file its own Analyzer from forFile.
+	mu     sync.Mutex
 	issues []Issue
 	fset   *token.FileSet
 	stack  parentStack
+	info   *types.Info
+
+	// closers maps functions in the current file to the indexes of the
+	// channel parameters they close.
+	closers map[string][]int
+
+	// fix enables attaching fixes to issues. The source of each analyzed
+	// file is then kept in files, keyed by filename, so the fixes are
+	// spliced into exactly what was analyzed.
+	fix   bool
+	files map[string][]byte
+
+	// bufferBytesThreshold is the buffer size in bytes above which a
+	// buffered channel is reported. Zero means defaultBufferBytesThreshold.
+	bufferBytesThreshold int64
+
+	// config selects the reported rules and their severities. The zero
+	// value reports the registry defaults.
+	config ruleConfig
+
+	// logFuncs are the path.Match patterns of calls treated as logging.
+	// Nil means defaultLogFuncs.
+	logFuncs []string
+
+	// safeFuncs names functions, as Name or Type.Method, whose sends are
+	// known to be safe, such as wrappers that send inside a select on the
+	// caller's behalf. Sends in them are not reported as send-without-select.
+	safeFuncs []string
+
+	// reportUnusedSuppressions reports suppression comments that did not
+	// suppress any issue.
+	reportUnusedSuppressions bool
+
+	// workers is the number of files analyzed at a time; values below 2
+	// analyze files one by one. See analyzeFiles.
+	workers int
+
+	// goVersion is the Go language version the analyzed code targets, such
+	// as go1.21, for checks that only apply to some versions. Empty means
+	// the current version.
+	goVersion string
+
+	// dropHandlers are the path.Match patterns of calls that record a value
+	// dropped by a non-blocking send. The drop-handler rule only runs when
+	// it is set.
+	dropHandlers []string
+
+	// filesAnalyzed counts the files parsed and analyzed.
+	filesAnalyzed int
+
+	// directive is the name of the comment directives, as in
+	// //<directive>:ignore. Empty means defaultDirective.
+	directive string
+
+	// limitDepth stops directory walks from descending more than maxDepth
+	// levels below the root; at 0 only the root's own files are analyzed.
+	limitDepth bool
+	maxDepth   int
+
+	// tests selects whether directory walks and globs analyze _test.go
+	// files: testsInclude (or ""), testsExclude or testsOnly.
+	tests string
+}
+
+// Values of the -tests flag.
+const (
+	testsInclude = "include"
+	testsExclude = "exclude"
+	testsOnly    = "only"
+)
+
+// wantFile reports whether a Go file found by a directory walk or glob
+// should be analyzed under the -tests mode.
+func (a *Analyzer) wantFile(path string) bool {
+	isTest := strings.HasSuffix(path, "_test.go")
+	switch a.tests {
+	case testsExclude:
+		return !isTest
+	case testsOnly:
+		return isTest
+	default:
+		return true
+	}
 }
 
 // getPosition converts ast node position information into a Position
@@ -75,71 +195,621 @@ func (p *parentStack) pop() {
 type OutputFormat string
 
 const (
-	OutputFormatText OutputFormat = "txt"
-	OutputFormatJSON OutputFormat = "json"
+	OutputFormatText   OutputFormat = "txt"
+	OutputFormatJSON   OutputFormat = "json"
+	OutputFormatEmacs  OutputFormat = "emacs"
+	OutputFormatVim    OutputFormat = "vim"
+	OutputFormatSARIF  OutputFormat = "sarif"
+	OutputFormatGitLab OutputFormat = "gitlab"
+	OutputFormatTAP    OutputFormat = "tap"
+	OutputFormatJUnit  OutputFormat = "junit"
+
+	OutputFormatCodeClimate OutputFormat = "codeclimate"
 )
 
+// outputFormats lists the valid -output values.
+var outputFormats = []OutputFormat{
+	OutputFormatText,
+	OutputFormatJSON,
+	OutputFormatEmacs,
+	OutputFormatVim,
+	OutputFormatSARIF,
+	OutputFormatGitLab,
+	OutputFormatTAP,
+	OutputFormatJUnit,
+	OutputFormatCodeClimate,
+}
+
 type JSONOutput struct {
 	Issues []JSONIssue `json:"issues"`
 	Total  int         `json:"total"`
 }
 
 type JSONIssue struct {
+	Rule        string   `json:"rule,omitempty"`
 	Severity    string   `json:"severity"`
 	Message     string   `json:"message"`
+	Func        string   `json:"func,omitempty"`
+	Package     string   `json:"package,omitempty"`
+	Fingerprint string   `json:"fingerprint"`
 	Position    Position `json:"position"`
 }
 
 func main() {
-	if err := run(); err != nil {
+	code, err := run(os.Args[1:], os.Stdout, os.Stderr)
+	if err != nil {
 		log.Fatalf("Error: %v", err)
 	}
+	os.Exit(code)
+}
+
+// options holds the parsed command line flags.
+type options struct {
+	path         string
+	output       OutputFormat
+	serve        string
+	compare      string
+	statusStderr bool
+	root         string
+	benchCorpus  string
+	dumpRules    string
+	exit         exitPolicy
+	fix          bool
+	dryRun       bool
+	config       ruleConfig
+	timeout      time.Duration
+	ignoreFile   string
+	cpuProfile   string
+	memProfile   string
+	tests        string
+	jsonFile     string
+	logFuncs     []string
+	safeFuncs    []string
+	dropHandlers []string
+	sort         func(a, b Issue) int
+	directive    string
+	summaryJSON  bool
+	summaryLine  bool
+	modules      bool
+	explain      bool
+	tui          bool
+	since        string
+	reportEmpty  bool
+	goVersion    string
+	workers      int
+	renderFrom   string
+	maxDepth     int
+	topRules     int
+
+	relativeToModule         bool
+	pathStyle                string
+	stdinFilename            string
+	junitGrouping            string
+	reportUnusedSuppressions bool
+
+	baseline       string
+	writeBaseline  string
+	baselineFormat string
+
+	bufferBytesThreshold int64
+}
+
+func parseFlags(args []string, stderr io.Writer) (*options, error) {
+	opts := &options{}
+	var output string
+
+	flags := flag.NewFlagSet("channelcheck", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	flags.StringVar(&opts.path, "path", ".", "Path to file or directory to analyze, a glob such as 'internal/**/*.go', or - to read one file from stdin")
+	flags.StringVar(&opts.stdinFilename, "stdin-filename", "<stdin>", "With -path=-, the file name to report issues at, e.g. the path of the editor buffer piped in")
+	flags.StringVar(&output, "output", "txt", "Output format: txt, json, emacs, vim, sarif, gitlab, codeclimate, tap or junit")
+	flags.StringVar(&opts.junitGrouping, "junit-grouping", junitByFile, "JUnit testcases with -output=junit: file (one per file, its issues as failures) or issue (one per issue)")
+	flags.StringVar(&opts.serve, "serve", "", "Serve the analysis HTTP API on this address (e.g. :8080) instead of analyzing -path")
+	flags.IntVar(&opts.topRules, "top-rules", 0, "Print the N rules with the most issues to stderr after the report")
+	flags.BoolVar(&opts.summaryJSON, "summary-json", false, "Print only aggregate counts as JSON instead of the -output report")
+	flags.BoolVar(&opts.summaryLine, "summary-line", false, "End the txt report with a line counting the issues by severity")
+	flags.StringVar(&opts.since, "since", "", "Only analyze the .go files below -path changed since this git ref, e.g. origin/main, or untracked")
+	flags.BoolVar(&opts.tui, "tui", false, "Browse the issues interactively, filtering by severity and rule, instead of printing the -output report")
+	flags.BoolVar(&opts.reportEmpty, "report-empty", true, "Print the txt or json report on a clean run; false prints nothing when there are no issues")
+	flags.BoolVar(&opts.explain, "explain", false, "Print a detailed rationale under each issue; txt output only")
+	flags.BoolVar(&opts.modules, "modules", false, "Print issue counts per package, then the issues grouped by package, instead of the -output report")
+	flags.StringVar(&opts.jsonFile, "json-file", "", "Also write the JSON report to this file, alongside the -output report on stdout")
+	flags.StringVar(&opts.renderFrom, "render-from", "", "Render a previous JSON report in the -output format instead of analyzing -path")
+	flags.StringVar(&opts.compare, "compare", "", "Previous JSON report; only issues not present in it are reported")
+	flags.BoolVar(&opts.statusStderr, "status-stderr", false, "Write a compact JSON run status to stderr when done")
+	flags.StringVar(&opts.dumpRules, "dump-rules", "", "Print the rule catalog in this format (json) and exit")
+	flags.StringVar(&opts.benchCorpus, "bench-corpus", "", "Developer mode: analyze this directory and report timing, memory and per-rule counts instead of issues")
+	failOn := flags.String("fail-on", "error", "Exit non-zero if any issue is at or above this severity: none, info, warning, error")
+	flags.BoolVar(&opts.exit.strict, "strict", false, "Exit non-zero on any issue, whatever its severity; reported severities are unchanged")
+	flags.BoolVar(&opts.exit.failOnParseError, "fail-on-parse-error", false, "Exit non-zero if any file fails to parse; parse errors never count toward -fail-on or -strict")
+	maxErrors := flags.Int("max-errors", -1, "Exit non-zero if more than this many ERROR issues are reported; -1 for no cap")
+	maxWarnings := flags.Int("max-warnings", -1, "Exit non-zero if more than this many WARNING issues are reported; -1 for no cap")
+	maxInfos := flags.Int("max-infos", -1, "Exit non-zero if more than this many INFO issues are reported; -1 for no cap")
+	flags.BoolVar(&opts.exit.exitZero, "exit-zero", false, "Always exit 0 when analysis succeeds, even if issues fail -fail-on")
+	flags.BoolVar(&opts.fix, "fix", false, "Rewrite files in place to apply the fixes offered by fixable rules")
+	flags.BoolVar(&opts.dryRun, "dry-run", false, "With -fix, print a diff of the fixes to stderr instead of writing files")
+	flags.IntVar(&opts.maxDepth, "max-depth", -1, "Directory levels below -path to descend into; 0 analyzes only the files directly in -path, negative means no limit")
+	flags.StringVar(&opts.tests, "tests", testsInclude, "Whether directories and globs include _test.go files: include, exclude or only")
+	flags.BoolVar(&opts.reportUnusedSuppressions, "report-unused-suppressions", false, "Report suppression comments that did not suppress any issue")
+	flags.StringVar(&opts.directive, "directive", defaultDirective, "Name of the comment directives, as in //<name>:ignore and //<name>:min-severity=")
+	flags.StringVar(&opts.ignoreFile, "ignore-file", "", "File of issue fingerprints to suppress, one per line; # starts a comment")
+	flags.StringVar(&opts.baseline, "baseline", "", "Baseline of accepted issues to suppress, as written by -write-baseline")
+	flags.StringVar(&opts.writeBaseline, "write-baseline", "", "Write the issues found to this file as a baseline")
+	baselineStrict := flags.Bool("baseline-strict", false, "With -baseline, exit non-zero if any issue not in the baseline remains, whatever its severity")
+	flags.StringVar(&opts.baselineFormat, "baseline-format", "", "Baseline format: json or text (one fingerprint per line); default is text for .txt files, json otherwise")
+	flags.StringVar(&opts.pathStyle, "path-style", pathStyleOS, "Separators in reported file paths: os, or slash for forward slashes on every OS")
+	flags.StringVar(&opts.root, "root", "", "Report file paths relative to this directory")
+	flags.BoolVar(&opts.relativeToModule, "relative-to-module", false, "Report file paths relative to the root of each file's module, where its go.mod is")
+	flags.Int64Var(&opts.bufferBytesThreshold, "buffer-bytes-threshold", defaultBufferBytesThreshold, "Report buffered channels whose buffer reserves more than this many bytes")
+	flags.IntVar(&opts.workers, "workers", 1, "Number of files to parse and analyze at a time; each file's syntax tree is released once analyzed")
+	flags.DurationVar(&opts.timeout, "timeout", 0, "Stop analyzing after this long (e.g. 30s) and report the partial results; 0 means no limit")
+	flags.StringVar(&opts.cpuProfile, "cpuprofile", "", "Write a pprof CPU profile of the run to this file")
+	flags.StringVar(&opts.memProfile, "memprofile", "", "Write a pprof heap profile to this file when the run ends")
+	logFuncs := flags.String("log-funcs", strings.Join(defaultLogFuncs, ","), "Comma-separated patterns of calls treated as logging by log-only-default, e.g. 'log.*,logger.*'")
+	dropHandlers := flags.String("drop-handler", "", "Comma-separated patterns of calls, e.g. 'metrics.Dropped', that the default of every non-blocking send must call")
+	safeFuncs := flags.String("safe-funcs", "", "Comma-separated functions, as Name or Type.Method, whose channel sends are not reported as send-without-select")
+	goVersion := flags.String("go", "", "Go version the code targets, e.g. 1.21, for version-specific checks; default is the go directive of the module containing -path, or the toolchain version")
+	sortKeys := flags.String("sort", defaultSort, "Comma-separated sort keys for the report: file, line, column, severity, rule, message")
+	preset := flags.String("preset", "default", "Rule preset: default, all, strict (all rules, severities promoted) or minimal (core rules only)")
+	rulesFrom := flags.String("rules-from", "", "JSON rule configuration to apply on top of -preset, from a file or an http(s) URL; local flags take precedence")
+	enable := flags.String("enable", "", "Comma-separated rule IDs to enable on top of -preset")
+	disable := flags.String("disable", "", "Comma-separated rule IDs to disable on top of -preset")
+	var warnAsError ruleList
+	flags.Var(&warnAsError, "warn-as-error", "Rule IDs whose issues are reported as ERROR, comma-separated; may be repeated")
+	if err := flags.Parse(args); err != nil {
+		return nil, err
+	}
+
+	var err error
+	if opts.exit.failOn, err = parseFailOn(*failOn); err != nil {
+		return nil, err
+	}
+
+	if !slices.Contains([]string{testsInclude, testsExclude, testsOnly}, opts.tests) {
+		return nil, fmt.Errorf("invalid -tests value %q: valid options are include, exclude, only", opts.tests)
+	}
+
+	for _, pattern := range strings.Split(*logFuncs, ",") {
+		pattern = strings.TrimSpace(pattern)
+		if _, err := path.Match(pattern, ""); err != nil {
+			return nil, fmt.Errorf("invalid -log-funcs pattern %q: %w", pattern, err)
+		}
+		opts.logFuncs = append(opts.logFuncs, pattern)
+	}
+
+	if *dropHandlers != "" {
+		for _, pattern := range strings.Split(*dropHandlers, ",") {
+			pattern = strings.TrimSpace(pattern)
+			if _, err := path.Match(pattern, ""); err != nil {
+				return nil, fmt.Errorf("invalid -drop-handler pattern %q: %w", pattern, err)
+			}
+			opts.dropHandlers = append(opts.dropHandlers, pattern)
+		}
+	}
+
+	if *safeFuncs != "" {
+		for _, name := range strings.Split(*safeFuncs, ",") {
+			opts.safeFuncs = append(opts.safeFuncs, strings.TrimSpace(name))
+		}
+	}
+
+	if opts.baselineFormat != "" && opts.baselineFormat != baselineJSON && opts.baselineFormat != baselineText {
+		return nil, fmt.Errorf("invalid -baseline-format value %q: valid options are json, text", opts.baselineFormat)
+	}
+	for severity, limit := range map[string]int{"ERROR": *maxErrors, "WARNING": *maxWarnings, "INFO": *maxInfos} {
+		if limit < -1 {
+			return nil, fmt.Errorf("invalid -max-%ss value %d: must be -1 (no cap) or at least 0", strings.ToLower(severity), limit)
+		}
+		if limit >= 0 {
+			if opts.exit.maxCount == nil {
+				opts.exit.maxCount = make(map[string]int)
+			}
+			opts.exit.maxCount[severity] = limit
+		}
+	}
+
+	if *baselineStrict {
+		if opts.baseline == "" {
+			return nil, fmt.Errorf("-baseline-strict requires -baseline")
+		}
+		// The exit code is decided on the issues left after the baseline
+		// is applied, so failing on any of them is the "no new issues"
+		// gate.
+		opts.exit.strict = true
+	}
+
+	if *goVersion == "" {
+		if opts.path == "-" {
+			opts.goVersion = defaultGoVersion(opts.stdinFilename)
+		} else {
+			opts.goVersion = defaultGoVersion(opts.path)
+		}
+	} else if opts.goVersion, err = parseGoVersion(*goVersion); err != nil {
+		return nil, err
+	}
+
+	if opts.workers < 1 {
+		return nil, fmt.Errorf("invalid -workers value %d: must be at least 1", opts.workers)
+	}
+	if opts.workers > 1 && opts.fix {
+		return nil, fmt.Errorf("-fix keeps every file in memory and cannot be used with -workers")
+	}
+
+	if opts.path == "-" && (opts.fix || opts.since != "" || opts.tui) {
+		return nil, fmt.Errorf("-path=- reads stdin and cannot be used with -fix, -since or -tui")
+	}
+
+	if opts.pathStyle != pathStyleOS && opts.pathStyle != pathStyleSlash {
+		return nil, fmt.Errorf("invalid -path-style value %q: valid options are os, slash", opts.pathStyle)
+	}
+
+	if opts.root != "" && opts.relativeToModule {
+		return nil, fmt.Errorf("-root and -relative-to-module cannot be used together")
+	}
+
+	if opts.sort, err = parseSort(*sortKeys); err != nil {
+		return nil, err
+	}
+
+	if opts.config, err = presetConfig(*preset); err != nil {
+		return nil, err
+	}
+	if *rulesFrom != "" {
+		external, err := loadExternalRules(*rulesFrom)
+		if err != nil {
+			return nil, err
+		}
+		if err := external.apply(opts.config); err != nil {
+			return nil, fmt.Errorf("invalid -rules-from %s: %w", *rulesFrom, err)
+		}
+		thresholdSet := false
+		flags.Visit(func(f *flag.Flag) {
+			thresholdSet = thresholdSet || f.Name == "buffer-bytes-threshold"
+		})
+		if external.BufferBytesThreshold != nil && !thresholdSet {
+			opts.bufferBytesThreshold = *external.BufferBytesThreshold
+		}
+	}
+	if err := opts.config.setEnabled(*enable, true); err != nil {
+		return nil, fmt.Errorf("invalid -enable value: %w", err)
+	}
+	if err := opts.config.setEnabled(*disable, false); err != nil {
+		return nil, fmt.Errorf("invalid -disable value: %w", err)
+	}
+	if err := opts.config.setSeverity(warnAsError, "ERROR"); err != nil {
+		return nil, fmt.Errorf("invalid -warn-as-error value: %w", err)
+	}
+
+	opts.output = OutputFormat(output)
+
+	if !slices.Contains(outputFormats, opts.output) {
+		valid := make([]string, len(outputFormats))
+		for i, format := range outputFormats {
+			valid[i] = string(format)
+		}
+		return nil, fmt.Errorf("invalid output format: %s. Valid options are: %s", output, strings.Join(valid, ", "))
+	}
+	if opts.junitGrouping != junitByFile && opts.junitGrouping != junitByIssue {
+		return nil, fmt.Errorf("invalid -junit-grouping value %q: valid options are file, issue", opts.junitGrouping)
+	}
+	if opts.explain && opts.output != OutputFormatText {
+		return nil, fmt.Errorf("-explain only applies to -output=txt")
+	}
+
+	return opts, nil
 }
 
-func run() error {
-	path := flag.String("path", ".", "Path to file or directory to analyze")
-	output := flag.String("output", "txt", "Output format: txt or json")
-	flag.Parse()
+// run executes the command line in args and returns the process exit code.
+func run(args []string, stdout, stderr io.Writer) (code int, err error) {
+	opts, err := parseFlags(args, stderr)
+	if err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return 0, nil
+		}
+		return 0, err
+	}
 
-	if path == nil || output == nil {
-		return fmt.Errorf("invalid flag values")
+	stopProfiling, err := startProfiling(opts.cpuProfile, opts.memProfile)
+	if err != nil {
+		return 0, err
 	}
+	defer func() {
+		if stopErr := stopProfiling(); stopErr != nil && err == nil {
+			err = stopErr
+		}
+	}()
 
-	outputFormat := OutputFormat(*output)
-	if outputFormat != OutputFormatText && outputFormat != OutputFormatJSON {
-		return fmt.Errorf("invalid output format: %s. Valid options are: txt, json", *output)
+	if opts.serve != "" {
+		return 0, serve(opts)
 	}
 
-	analyzer := &Analyzer{
-		fset: token.NewFileSet(),
+	if opts.dumpRules != "" {
+		return 0, dumpRules(stdout, opts.dumpRules)
 	}
+
+	if opts.benchCorpus != "" {
+		result, err := benchCorpus(opts.benchCorpus)
+		if err != nil {
+			return 0, err
+		}
+		return 0, printBench(stdout, result)
+	}
+
+	if opts.renderFrom != "" {
+		issues, err := loadReport(opts.renderFrom)
+		if err != nil {
+			return 0, err
+		}
+		applyPathStyle(issues, opts.pathStyle)
+		if err := printReport(stdout, opts, issues); err != nil {
+			return 0, fmt.Errorf("error printing output: %w", err)
+		}
+		return exitCode(issues, opts.exit), nil
+	}
+
+	issues, err := analyzeAndReport(opts, stdout, stderr)
+	if err == nil {
+		code = exitCode(issues, opts.exit)
+	}
+	if opts.statusStderr {
+		if statusErr := writeStatus(stderr, issues, err, code); statusErr != nil && err == nil {
+			err = statusErr
+		}
+	}
+	return code, err
+}
+
+// loadSuppressions reads the -ignore-file and -baseline fingerprints, either
+// of which is nil when its flag is unset.
+func loadSuppressions(opts *options) (ignored, baseline map[string]bool, err error) {
+	if opts.ignoreFile != "" {
+		if ignored, err = loadIgnoreFile(opts.ignoreFile); err != nil {
+			return nil, nil, err
+		}
+	}
+	if opts.baseline != "" {
+		if baseline, err = loadBaseline(opts.baseline, opts.baselineFormat); err != nil {
+			return nil, nil, err
+		}
+	}
+	return ignored, baseline, nil
+}
+
+// newAnalyzer returns an Analyzer configured by the rule, fix and walk
+// options.
+func newAnalyzer(opts *options) *Analyzer {
+	return &Analyzer{
+		fset:                 token.NewFileSet(),
+		bufferBytesThreshold: opts.bufferBytesThreshold,
+		fix:                  opts.fix,
+		config:               opts.config,
+		tests:                opts.tests,
+		logFuncs:             opts.logFuncs,
+		safeFuncs:            opts.safeFuncs,
+		dropHandlers:         opts.dropHandlers,
+		goVersion:            opts.goVersion,
+		workers:              opts.workers,
+		directive:            opts.directive,
+		limitDepth:           opts.maxDepth >= 0,
+		maxDepth:             opts.maxDepth,
+
+		reportUnusedSuppressions: opts.reportUnusedSuppressions,
+	}
+}
+
+// analyzeAndReport analyzes the configured path and prints the report. It
+// returns the issues that were reported.
+func analyzeAndReport(opts *options, stdout, stderr io.Writer) ([]Issue, error) {
+	ignored, baseline, err := loadSuppressions(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	analyzer := newAnalyzer(opts)
 	if analyzer.fset == nil {
-		return fmt.Errorf("failed to create token.FileSet")
+		return nil, fmt.Errorf("failed to create token.FileSet")
 	}
 
-	if err := analyzer.analyzePath(*path); err != nil {
-		return fmt.Errorf("error analyzing path: %w", err)
+	ctx := context.Background()
+	if opts.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, opts.timeout)
+		defer cancel()
 	}
 
-	if err := printOutput(outputFormat, analyzer.issues); err != nil {
-		return fmt.Errorf("error printing output: %w", err)
+	if opts.path == "-" {
+		err = analyzer.analyzeStdin(stdin, opts.stdinFilename)
+	} else if opts.since != "" {
+		err = analyzer.analyzeSince(ctx, opts.path, opts.since)
+	} else {
+		err = analyzer.analyzePath(ctx, opts.path)
+	}
+	if err != nil {
+		if !errors.Is(err, context.DeadlineExceeded) {
+			return nil, fmt.Errorf("error analyzing path: %w", err)
+		}
+		// Report what was analyzed before the deadline.
+		if _, err := fmt.Fprintf(stderr, "analysis timed out after %s\n", opts.timeout); err != nil {
+			return nil, err
+		}
 	}
 
-	return nil
+	issues := analyzer.issues
+	if opts.root != "" {
+		if err := relativizePaths(issues, opts.root, stderr); err != nil {
+			return nil, err
+		}
+	}
+	if opts.relativeToModule {
+		if err := relativizeToModules(issues, stderr); err != nil {
+			return nil, err
+		}
+	}
+
+	// Fingerprints are taken after -root so an ignore file can be shared
+	// between checkouts.
+	if ignored != nil {
+		issues = filterIgnored(issues, ignored)
+	}
+
+	// Only fix what is left to report: findings in the ignore file or the
+	// baseline keep their source. The diff of a dry run goes to stderr so
+	// stdout stays a valid report.
+	if opts.fix {
+		var err error
+		if issues, err = analyzer.applyFixes(issues, baseline, opts.dryRun, stderr); err != nil {
+			return nil, fmt.Errorf("error applying fixes: %w", err)
+		}
+	}
+
+	slices.SortStableFunc(issues, opts.sort)
+
+	// The baseline is written before the old one is applied, so passing
+	// both -baseline and -write-baseline refreshes it.
+	if opts.writeBaseline != "" {
+		if err := writeBaseline(opts.writeBaseline, opts.baselineFormat, issues); err != nil {
+			return nil, err
+		}
+	}
+	if baseline != nil {
+		issues = filterIgnored(issues, baseline)
+	}
+
+	if opts.compare != "" {
+		previous, err := loadReport(opts.compare)
+		if err != nil {
+			return nil, fmt.Errorf("error loading comparison report: %w", err)
+		}
+		var resolved int
+		issues, resolved = compareIssues(previous, issues)
+		if _, err := fmt.Fprintf(stderr, "%d new, %d resolved\n", len(issues), resolved); err != nil {
+			return nil, err
+		}
+	}
+
+	applyPathStyle(issues, opts.pathStyle)
+
+	if opts.tui {
+		if err := runTUI(stdin, stdout, issues); err != nil {
+			return nil, fmt.Errorf("error running -tui: %w", err)
+		}
+	} else if opts.summaryJSON {
+		if err := printSummaryJSON(stdout, summarize(issues, analyzer.filesAnalyzed)); err != nil {
+			return nil, fmt.Errorf("error printing summary: %w", err)
+		}
+	} else if opts.modules {
+		if err := printModules(stdout, issues); err != nil {
+			return nil, fmt.Errorf("error printing output: %w", err)
+		}
+	} else if err := printReport(stdout, opts, issues); err != nil {
+		return nil, fmt.Errorf("error printing output: %w", err)
+	}
+	if opts.jsonFile != "" {
+		if err := writeJSONFile(opts.jsonFile, issues); err != nil {
+			return nil, err
+		}
+	}
+	if opts.topRules > 0 {
+		if err := printTopRules(stderr, issues, opts.topRules); err != nil {
+			return nil, err
+		}
+	}
+
+	return issues, nil
+}
+
+// RunStatus is the machine-readable summary written by -status-stderr.
+type RunStatus struct {
+	Issues int  `json:"issues"`
+	Errors int  `json:"errors"`
+	Failed bool `json:"failed"`
 }
 
-func printOutput(format OutputFormat, issues []Issue) error {
+func writeStatus(w io.Writer, issues []Issue, runErr error, code int) error {
+	status := RunStatus{
+		Issues: len(issues),
+		Failed: code != 0,
+	}
+	if runErr != nil {
+		status.Errors = 1
+		status.Failed = true
+	}
+
+	jsonBytes, err := json.Marshal(status)
+	if err != nil {
+		return fmt.Errorf("error marshaling status: %w", err)
+	}
+
+	_, err = fmt.Fprintln(w, string(jsonBytes))
+	return err
+}
+
+// printReport prints issues in the -output format, with each rule's
+// rationale under its issues for -explain and -junit-grouping applied to
+// JUnit reports. With -summary-line, a txt report with issues ends with their
+// count by severity. With -report-empty=false, a clean txt or json report is
+// left out entirely.
+func printReport(w io.Writer, opts *options, issues []Issue) error {
+	if !opts.reportEmpty && len(issues) == 0 && (opts.output == OutputFormatText || opts.output == OutputFormatJSON) {
+		return nil
+	}
+
+	var err error
+	switch {
+	case opts.explain:
+		err = writeText(w, issues, true)
+	case opts.output == OutputFormatJUnit:
+		err = printJUnit(w, issues, opts.junitGrouping)
+	default:
+		err = printOutput(w, opts.output, issues)
+	}
+	if err != nil || !opts.summaryLine || opts.output != OutputFormatText || len(issues) == 0 {
+		return err
+	}
+	if _, err := fmt.Fprintln(w); err != nil {
+		return err
+	}
+	return printSeverityCounts(w, issues)
+}
+
+func printOutput(w io.Writer, format OutputFormat, issues []Issue) error {
 	switch format {
 	case OutputFormatJSON:
-		return printJSON(issues)
+		return printJSON(w, issues)
 	case OutputFormatText:
-		return printText(issues)
+		return printText(w, issues)
+	case OutputFormatEmacs:
+		return printEmacs(w, issues)
+	case OutputFormatVim:
+		return printVim(w, issues)
+	case OutputFormatSARIF:
+		return printSARIF(w, issues)
+	case OutputFormatGitLab:
+		return printGitLab(w, issues)
+	case OutputFormatTAP:
+		return printTAP(w, issues)
+	case OutputFormatJUnit:
+		return printJUnit(w, issues, junitByFile)
+	case OutputFormatCodeClimate:
+		return printCodeClimate(w, issues)
 	default:
 		return fmt.Errorf("unknown output format: %s", format)
 	}
 }
 
-func printJSON(issues []Issue) error {
+// writeJSONFile writes the JSON report to path, for -json-file.
+func writeJSONFile(path string, issues []Issue) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("error creating JSON report: %w", err)
+	}
+	if err := printJSON(f, issues); err != nil {
+		return errors.Join(fmt.Errorf("error writing JSON report: %w", err), f.Close())
+	}
+	return f.Close()
+}
+
+func newJSONOutput(issues []Issue) JSONOutput {
 	output := JSONOutput{
 		Total:  len(issues),
 		Issues: make([]JSONIssue, len(issues)),
@@ -147,65 +817,196 @@ func printJSON(issues []Issue) error {
 
 	for i, issue := range issues {
 		output.Issues[i] = JSONIssue{
-			Severity: issue.Severity,
-			Message:  issue.Message,
-			Position: issue.Pos,
+			Rule:        issue.Rule,
+			Severity:    issue.Severity,
+			Message:     issue.Message,
+			Func:        issue.Func,
+			Package:     issue.Package,
+			Fingerprint: issue.Fingerprint(),
+			Position:    issue.Pos,
 		}
 	}
+	return output
+}
+
+func printJSON(w io.Writer, issues []Issue) error {
+	output := newJSONOutput(issues)
 
 	jsonBytes, err := json.MarshalIndent(output, "", "  ")
 	if err != nil {
 		return fmt.Errorf("error marshaling JSON: %w", err)
 	}
 	
-	_, err = fmt.Println(string(jsonBytes))
+	_, err = fmt.Fprintln(w, string(jsonBytes))
 	return err
 }
 
-func printText(issues []Issue) error {
+func printText(w io.Writer, issues []Issue) error {
+	return writeText(w, issues, false)
+}
+
+// writeText writes the txt report. With explain, the rationale of each
+// issue's rule follows it, indented.
+func writeText(w io.Writer, issues []Issue, explain bool) error {
 	if len(issues) == 0 {
-		_, err := fmt.Println("No issues found!")
+		_, err := fmt.Fprintln(w, "No issues found!")
 		return err
 	}
 
-	_, err := fmt.Printf("Found %d potential issues:\n\n", len(issues))
+	_, err := fmt.Fprintf(w, "Found %d potential issues:\n\n", len(issues))
 	if err != nil {
 		return err
 	}
 
 	for _, issue := range issues {
-		if _, err := fmt.Printf("[%s] %s: %s\n", issue.Severity, issue.Pos, issue.Message); err != nil {
+		if _, err := fmt.Fprintf(w, "[%s] %s: %s\n", issue.Severity, issue.Pos, issue.Message); err != nil {
 			return err
 		}
+		if explain {
+			if err := writeRationale(w, issue.Rule); err != nil {
+				return err
+			}
+		}
 	}
 	return nil
 }
 
-func (a *Analyzer) analyzePath(path string) error {
+// analyzePath analyzes a file, a directory tree or the files matching a glob.
+// It stops before the next file once ctx is done and returns ctx's error;
+// the issues found so far are kept.
+func (a *Analyzer) analyzePath(ctx context.Context, path string) error {
+	if hasGlobMeta(path) {
+		files, err := expandGlob(path)
+		if err != nil {
+			return err
+		}
+		var wanted []string
+		for _, file := range files {
+			if a.wantFile(file) {
+				wanted = append(wanted, file)
+			}
+		}
+		return a.analyzeFiles(ctx, wanted)
+	}
+
 	fileInfo, err := os.Stat(path)
 	if err != nil {
 		return fmt.Errorf("error accessing path: %w", err)
 	}
 
 	if fileInfo.IsDir() {
-		return filepath.Walk(path, func(path string, info os.FileInfo, err error) error {
+		root := path
+		var files []string
+		err := filepath.Walk(path, func(path string, info os.FileInfo, err error) error {
 			if err != nil {
 				return err
 			}
-			if !info.IsDir() && strings.HasSuffix(path, ".go") {
-				if err := a.analyzeFile(path); err != nil {
-					return fmt.Errorf("error analyzing file %s: %w", path, err)
+			if info.IsDir() && a.limitDepth && path != root {
+				rel, err := filepath.Rel(root, path)
+				if err != nil {
+					return err
+				}
+				if depth := strings.Count(filepath.ToSlash(rel), "/") + 1; depth > a.maxDepth {
+					return filepath.SkipDir
 				}
 			}
+			if !info.IsDir() && strings.HasSuffix(path, ".go") && a.wantFile(path) {
+				files = append(files, path)
+			}
 			return nil
 		})
+		if err != nil {
+			return err
+		}
+		return a.analyzeFiles(ctx, files)
 	}
 
-	return a.analyzeFile(path)
+	if err := a.analyzeFile(ctx, path); err != nil && !a.recordParseError(err) {
+		return err
+	}
+	return nil
 }
 
-func (a *Analyzer) analyzeFile(path string) error {
-	file, err := parser.ParseFile(a.fset, path, nil, parser.AllErrors)
+// analyzeFileHook, if set, is called before each file is analyzed. Tests use
+// it to slow the analysis down.
+var analyzeFileHook func(ctx context.Context, path string)
+
+func (a *Analyzer) analyzeFile(ctx context.Context, path string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if analyzeFileHook != nil {
+		analyzeFileHook(ctx, path)
+	}
+	err := a.analyzeSource(path, nil)
+	if releaseFileHook != nil && !a.fix {
+		releaseFileHook(path)
+	}
+	return err
+}
+
+// stdin is read by -path=- and -tui. Tests replace it.
+var stdin io.Reader = os.Stdin
+
+// analyzeStdin analyzes the single file read from r, reporting its issues
+// at filename.
+func (a *Analyzer) analyzeStdin(r io.Reader, filename string) error {
+	src, err := io.ReadAll(r)
+	if err != nil {
+		return fmt.Errorf("error reading stdin: %w", err)
+	}
+	if err := a.analyzeSource(filename, src); err != nil && !a.recordParseError(err) {
+		return err
+	}
+	return nil
+}
+
+// recordParseError reports err as a parse-error issue at the first syntax
+// error if it is one, so a file that does not parse does not abort the
+// analysis of the others. It returns false for any other error.
+func (a *Analyzer) recordParseError(err error) bool {
+	var list scanner.ErrorList
+	if !errors.As(err, &list) || len(list) == 0 {
+		return false
+	}
+
+	first := list[0]
+	message := "file does not parse: " + first.Msg
+	if len(list) > 1 {
+		message += fmt.Sprintf(" (and %d more errors)", len(list)-1)
+	}
+	a.addIssue(Issue{
+		Rule: RuleParseError,
+		Pos: Position{
+			Filename:    first.Pos.Filename,
+			StartLine:   first.Pos.Line,
+			StartColumn: first.Pos.Column,
+			EndLine:     first.Pos.Line,
+			EndColumn:   first.Pos.Column,
+		},
+		Message:  message,
+		Severity: "ERROR",
+	})
+	return true
+}
+
+// analyzeSource parses and analyzes a single file. If src is nil the file is
+// read from disk, otherwise src is used as the file contents.
+func (a *Analyzer) analyzeSource(path string, src []byte) error {
+	if a.fix && src == nil {
+		var err error
+		if src, err = os.ReadFile(path); err != nil {
+			return fmt.Errorf("error reading file: %w", err)
+		}
+	}
+	// A nil []byte must not reach ParseFile as a non-nil interface value,
+	// otherwise it parses an empty file instead of reading path.
+	var source any
+	if src != nil {
+		source = src
+	}
+
+	file, err := parser.ParseFile(a.fset, path, source, parser.AllErrors|parser.ParseComments)
 	if err != nil {
 		return fmt.Errorf("error parsing file: %w", err)
 	}
@@ -214,7 +1015,57 @@ func (a *Analyzer) analyzeFile(path string) error {
 		return fmt.Errorf("parsed file is nil")
 	}
 
-	a.analyze(file)
+	directive := a.directive
+	if directive == "" {
+		directive = defaultDirective
+	}
+	minSeverity, err := fileMinSeverity(file, directive)
+	if err != nil {
+		return err
+	}
+
+	// The file's issues are collected, annotated and filtered on their own,
+	// then added to a in one step, so issues reported meanwhile by other
+	// goroutines or files are never touched by this file's directives.
+	fa := a.forFile()
+	fa.typeCheck(file)
+	fa.analyze(file)
+	for i := range fa.issues {
+		fa.issues[i].Package = file.Name.Name
+	}
+	if minSeverity != "" {
+		fa.issues = filterSeverity(fa.issues, minSeverity)
+	}
+	suppressions := fileSuppressions(a.fset, file, directive)
+	if len(suppressions) > 0 {
+		var used []bool
+		fa.issues, used = filterSuppressed(fa.issues, suppressions)
+		if a.reportUnusedSuppressions {
+			for i, s := range suppressions {
+				if used[i] {
+					continue
+				}
+				fa.addIssue(Issue{
+					Rule:     RuleUnusedSuppression,
+					Pos:      a.getPosition(s.pos, s.end),
+					Message:  "suppression comment did not suppress any issue — remove it",
+					Severity: "WARNING",
+					Package:  file.Name.Name,
+				})
+			}
+		}
+	}
+
+	fa.filesAnalyzed = 1
+	a.merge(fa)
+	if a.fix {
+		a.mu.Lock()
+		defer a.mu.Unlock()
+		if a.files == nil {
+			a.files = make(map[string][]byte)
+		}
+		a.files[path] = src
+	}
 	return nil
 }
 
@@ -225,6 +1076,7 @@ func (a *Analyzer) analyze(file *ast.File) {
 
 	// Reset the stack for each file
 	a.stack = parentStack{}
+	a.closers = closingFuncs(file)
 
 	ast.Inspect(file, func(n ast.Node) bool {
 		if n == nil {
@@ -240,14 +1092,44 @@ func (a *Analyzer) analyze(file *ast.File) {
 		case *ast.SendStmt:
 			if node != nil {
 				a.checkChannelSend(node)
+				a.checkSend(node)
 			}
 		case *ast.CallExpr:
 			if node != nil {
 				a.checkChannelCreation(node)
+				a.checkContextValue(node)
+			}
+		case *ast.SelectStmt:
+			if node != nil {
+				a.checkSelect(node)
+			}
+		case *ast.ForStmt:
+			if node != nil {
+				a.checkForStmt(node)
+			}
+		case *ast.IfStmt:
+			if node != nil {
+				a.checkInvertedCommaOk(node)
+			}
+		case *ast.RangeStmt:
+			if node != nil {
+				a.checkRangeSignal(node)
+			}
+		case *ast.AssignStmt:
+			if node != nil {
+				a.checkUnusedReceive(node)
+			}
+		case *ast.UnaryExpr:
+			if node != nil && node.Op == token.ARROW {
+				a.checkRecvOperand(node)
+				a.checkOnceBlocking(node)
+				a.checkPackageChan(node, node.X)
 			}
 		}
 		return true
 	})
+
+	a.checkFile(file)
 }
 
 func (a *Analyzer) checkChannelSend(node *ast.SendStmt) {
@@ -263,11 +1145,20 @@ func (a *Analyzer) checkChannelSend(node *ast.SendStmt) {
 		}
 	}
 
-	if !inSelect {
+	funcName := a.enclosingFuncName()
+	if !inSelect && !slices.Contains(a.safeFuncs, funcName) {
+		message := "channel send without select statement may block indefinitely"
+		if field := a.fieldPath(node.Chan); field != "" {
+			// Field channels are often shared across goroutines and
+			// reassigned; naming the field helps triage.
+			message += fmt.Sprintf(" (field %s)", field)
+		}
 		a.addIssue(Issue{
+			Rule:     RuleSendWithoutSelect,
 			Pos:      a.getPosition(node.Pos(), node.End()),
-			Message:  "channel send without select statement may block indefinitely",
+			Message:  message,
 			Severity: "WARNING",
+			Func:     funcName,
 		})
 	}
 }
@@ -280,18 +1171,32 @@ func (a *Analyzer) checkChannelCreation(node *ast.CallExpr) {
 
 	if len(node.Args) > 0 {
 		if chanType, ok := node.Args[0].(*ast.ChanType); ok && chanType != nil {
+			a.checkFuncChan(node, chanType)
+			a.checkChanOfChan(node, chanType)
 			// Check if buffer size is specified
 			if len(node.Args) == 1 {
 				a.addIssue(Issue{
+					Rule:     RuleUnbufferedChannel,
 					Pos:      a.getPosition(node.Pos(), node.End()),
 					Message:  "unbuffered channel creation detected - consider specifying buffer size",
 					Severity: "INFO",
+					fix:      a.bufferFix(node),
 				})
+			} else {
+				a.checkBufferSize(node, chanType)
 			}
 		}
 	}
 }
 
 func (a *Analyzer) addIssue(issue Issue) {
+	if !a.ruleEnabled(issue.Rule) {
+		return
+	}
+	if severity, ok := a.config.severity[issue.Rule]; ok {
+		issue.Severity = severity
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
 	a.issues = append(a.issues, issue)
 }
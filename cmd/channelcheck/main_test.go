@@ -5,7 +5,9 @@ import (
 	"go/ast"
 	"go/parser"
 	"go/token"
+	"path/filepath"
 	"strings"
+	"sync"
 	"testing"
 )
 
@@ -238,3 +240,168 @@ func formatIssues(issues []Issue) string {
 	}
 	return result.String()
 }
+
+func TestAnalyzer_ConcurrentAddIssue(t *testing.T) {
+	t.Parallel()
+
+	// Checks report from other goroutines while files go through the
+	// usual per-file path, which filters and annotates the file's issues.
+	const source = `package test
+
+func send(ch chan int) {
+	done := make(chan struct{})
+	ch <- 1
+	<-done
+}
+`
+	const files = 20
+	sequential := &Analyzer{fset: token.NewFileSet()}
+	if err := sequential.analyzeSource("file.go", []byte(source)); err != nil {
+		t.Fatalf("analysis failed: %v", err)
+	}
+	perFile := len(sequential.issues)
+	if perFile == 0 {
+		t.Fatalf("expected issues in the source")
+	}
+
+	const workers, perWorker = 32, 100
+	analyzer := &Analyzer{fset: token.NewFileSet()}
+	rules := []string{RuleSendWithoutSelect, RuleUnbufferedChannel}
+
+	var wg sync.WaitGroup
+	for w := range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range perWorker {
+				analyzer.addIssue(Issue{
+					Rule:     rules[i%len(rules)],
+					Pos:      Position{Filename: fmt.Sprintf("worker%d.go", w), StartLine: i + 1},
+					Message:  "concurrent issue",
+					Severity: "WARNING",
+				})
+			}
+		}()
+	}
+	for i := range files {
+		if err := analyzer.analyzeSource(fmt.Sprintf("file%d.go", i), []byte(source)); err != nil {
+			t.Fatalf("analysis failed: %v", err)
+		}
+	}
+	wg.Wait()
+
+	if got, want := len(analyzer.issues), workers*perWorker+files*perFile; got != want {
+		t.Fatalf("got %d issues, want %d", got, want)
+	}
+	var concurrent []Issue
+	seen := make(map[string]bool, len(analyzer.issues))
+	for _, issue := range analyzer.issues {
+		seen[issue.Fingerprint()] = true
+		if issue.Message == "concurrent issue" {
+			concurrent = append(concurrent, issue)
+		}
+	}
+	counts := countByRule(concurrent)
+	for _, rule := range rules {
+		if got, want := counts[rule], workers*perWorker/len(rules); got != want {
+			t.Errorf("rule %s: got %d concurrent issues, want %d", rule, got, want)
+		}
+	}
+	if len(seen) != workers*perWorker+files*perFile {
+		t.Errorf("got %d distinct issues, want %d", len(seen), workers*perWorker+files*perFile)
+	}
+}
+
+func TestAnalyzer_ConcurrentAnalyzeSource(t *testing.T) {
+	t.Parallel()
+
+	// Each file's directives and package name must apply to its own issues
+	// only, not to those other files or goroutines report meanwhile.
+	sources := map[string]struct {
+		source string
+		want   int
+	}{
+		"plain": {want: 2, source: `package plain
+
+func send(ch chan int) {
+	done := make(chan struct{})
+	ch <- 1
+	<-done
+}
+`},
+		"quiet": {want: 0, source: `//channelcheck:min-severity=error
+package quiet
+
+func send(ch chan int) {
+	done := make(chan struct{})
+	ch <- 1
+	<-done
+}
+`},
+		"ignored": {want: 1, source: `package ignored
+
+func send(ch chan int) {
+	done := make(chan struct{})
+	ch <- 1 //channelcheck:ignore
+	<-done
+}
+`},
+	}
+
+	const files, reporters, perReporter = 50, 8, 2000
+	analyzer := &Analyzer{fset: token.NewFileSet()}
+
+	var wg sync.WaitGroup
+	for r := range reporters {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range perReporter {
+				analyzer.addIssue(Issue{
+					Rule:     RuleUnbufferedChannel,
+					Pos:      Position{Filename: fmt.Sprintf("reporter%d.go", r), StartLine: i + 1},
+					Message:  "concurrent issue",
+					Severity: "INFO",
+				})
+			}
+		}()
+	}
+	for pkg, tt := range sources {
+		for i := range files {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				if err := analyzer.analyzeSource(fmt.Sprintf("%s%d.go", pkg, i), []byte(tt.source)); err != nil {
+					t.Errorf("analysis failed: %v", err)
+				}
+			}()
+		}
+	}
+	wg.Wait()
+
+	counts := make(map[string]int)
+	for _, issue := range analyzer.issues {
+		if issue.Message == "concurrent issue" {
+			if issue.Package != "" {
+				t.Fatalf("concurrent issue got package %q", issue.Package)
+			}
+			counts[""]++
+			continue
+		}
+		if !strings.HasPrefix(filepath.Base(issue.Pos.Filename), issue.Package) {
+			t.Fatalf("issue in %s got package %q", issue.Pos.Filename, issue.Package)
+		}
+		counts[issue.Package]++
+	}
+	if got, want := counts[""], reporters*perReporter; got != want {
+		t.Errorf("got %d concurrent issues, want %d", got, want)
+	}
+	for pkg, tt := range sources {
+		if got, want := counts[pkg], files*tt.want; got != want {
+			t.Errorf("package %s: got %d issues, want %d", pkg, got, want)
+		}
+	}
+	if got, want := analyzer.filesAnalyzed, files*len(sources); got != want {
+		t.Errorf("got %d files analyzed, want %d", got, want)
+	}
+}
@@ -0,0 +1,48 @@
+package main
+
+import (
+	"fmt"
+	"io"
+)
+
+// severityTag abbreviates a severity to the single letter used by editor
+// formats: E, W or I.
+func severityTag(severity string) string {
+	switch severity {
+	case "ERROR":
+		return "E"
+	case "WARNING":
+		return "W"
+	default:
+		return "I"
+	}
+}
+
+// printEmacs writes one GNU-style line per issue,
+// `file:line.col-endline.endcol: [W] message`, which Emacs compilation mode
+// recognizes including the column range.
+func printEmacs(w io.Writer, issues []Issue) error {
+	for _, issue := range issues {
+		p := issue.Pos
+		if _, err := fmt.Fprintf(w, "%s:%d.%d-%d.%d: [%s] %s\n",
+			p.Filename, p.StartLine, p.StartColumn, p.EndLine, p.EndColumn,
+			severityTag(issue.Severity), issue.Message); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// printVim writes one `file:line:col: [W] message` line per issue, which the
+// errorformat `%f:%l:%c:\ [%t]\ %m` parses including the issue type.
+func printVim(w io.Writer, issues []Issue) error {
+	for _, issue := range issues {
+		p := issue.Pos
+		if _, err := fmt.Fprintf(w, "%s:%d:%d: [%s] %s\n",
+			p.Filename, p.StartLine, p.StartColumn,
+			severityTag(issue.Severity), issue.Message); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+)
+
+// sampleIssues is a fixed issue set for output format tests.
+var sampleIssues = []Issue{
+	{
+		Rule:     RuleSendWithoutSelect,
+		Pos:      Position{Filename: "pkg/worker.go", StartLine: 15, StartColumn: 2, EndLine: 15, EndColumn: 9},
+		Message:  "channel send without select statement may block indefinitely",
+		Severity: "WARNING",
+	},
+	{
+		Rule:     RuleUnbufferedChannel,
+		Pos:      Position{Filename: "pkg/worker.go", StartLine: 10, StartColumn: 8, EndLine: 11, EndColumn: 3},
+		Message:  "unbuffered channel creation detected - consider specifying buffer size",
+		Severity: "INFO",
+	},
+}
+
+func TestPrintEditorFormats(t *testing.T) {
+	tests := []struct {
+		format   OutputFormat
+		expected string
+	}{
+		{
+			format: OutputFormatEmacs,
+			expected: "pkg/worker.go:15.2-15.9: [W] channel send without select statement may block indefinitely\n" +
+				"pkg/worker.go:10.8-11.3: [I] unbuffered channel creation detected - consider specifying buffer size\n",
+		},
+		{
+			format: OutputFormatVim,
+			expected: "pkg/worker.go:15:2: [W] channel send without select statement may block indefinitely\n" +
+				"pkg/worker.go:10:8: [I] unbuffered channel creation detected - consider specifying buffer size\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.format), func(t *testing.T) {
+			var out bytes.Buffer
+			if err := printOutput(&out, tt.format, sampleIssues); err != nil {
+				t.Fatalf("printOutput failed: %v", err)
+			}
+			if out.String() != tt.expected {
+				t.Errorf("got:\n%s\nwant:\n%s", out.String(), tt.expected)
+			}
+		})
+	}
+}
+
+func TestSeverityTag(t *testing.T) {
+	for severity, want := range map[string]string{"ERROR": "E", "WARNING": "W", "INFO": "I"} {
+		if got := severityTag(severity); got != want {
+			t.Errorf("severityTag(%q) = %q, want %q", severity, got, want)
+		}
+	}
+}
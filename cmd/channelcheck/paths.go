@@ -0,0 +1,117 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// relativizePaths rewrites issue filenames relative to root. Files outside
+// root keep their original filename and produce a warning on w.
+func relativizePaths(issues []Issue, root string, w io.Writer) error {
+	absRoot, err := filepath.Abs(root)
+	if err != nil {
+		return fmt.Errorf("error resolving root %s: %w", root, err)
+	}
+
+	warned := make(map[string]bool)
+	for i := range issues {
+		filename := issues[i].Pos.Filename
+		rel, ok := relativeTo(absRoot, filename)
+		if !ok {
+			if !warned[filename] {
+				warned[filename] = true
+				if _, err := fmt.Fprintf(w, "warning: %s is not inside root %s; keeping its path\n", filename, root); err != nil {
+					return err
+				}
+			}
+			continue
+		}
+		issues[i].Pos.Filename = rel
+	}
+	return nil
+}
+
+// relativeTo returns filename relative to the absolute directory base, and
+// whether filename is inside base.
+func relativeTo(base, filename string) (string, bool) {
+	absFile, err := filepath.Abs(filename)
+	if err != nil {
+		return "", false
+	}
+	rel, err := filepath.Rel(base, absFile)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", false
+	}
+	return rel, true
+}
+
+// moduleRoots finds the module root, the nearest directory containing
+// go.mod, of each directory, caching every lookup.
+type moduleRoots map[string]string
+
+// find returns the module root of the absolute directory dir, or "" if it is
+// not inside a module.
+func (roots moduleRoots) find(dir string) string {
+	if root, ok := roots[dir]; ok {
+		return root
+	}
+	root := ""
+	if _, err := os.Stat(filepath.Join(dir, "go.mod")); err == nil {
+		root = dir
+	} else if parent := filepath.Dir(dir); parent != dir {
+		root = roots.find(parent)
+	}
+	roots[dir] = root
+	return root
+}
+
+// relativizeToModules rewrites issue filenames relative to the root of the
+// module containing each file. Files outside any module keep their original
+// filename and produce a warning on w.
+func relativizeToModules(issues []Issue, w io.Writer) error {
+	roots := make(moduleRoots)
+	warned := make(map[string]bool)
+	for i := range issues {
+		filename := issues[i].Pos.Filename
+		absFile, err := filepath.Abs(filename)
+		if err != nil {
+			return fmt.Errorf("error resolving %s: %w", filename, err)
+		}
+		root := roots.find(filepath.Dir(absFile))
+		if root == "" {
+			if !warned[filename] {
+				warned[filename] = true
+				if _, err := fmt.Fprintf(w, "warning: %s is not inside a module; keeping its path\n", filename); err != nil {
+					return err
+				}
+			}
+			continue
+		}
+		if rel, ok := relativeTo(root, absFile); ok {
+			issues[i].Pos.Filename = rel
+		}
+	}
+	return nil
+}
+
+// Path styles accepted by -path-style.
+const (
+	pathStyleOS    = "os"
+	pathStyleSlash = "slash"
+)
+
+// applyPathStyle rewrites issue filenames for printing. With pathStyleSlash
+// every backslash becomes a forward slash, whatever the host OS, so reports
+// produced on Windows read the same as elsewhere; pathStyleOS leaves them
+// unchanged.
+func applyPathStyle(issues []Issue, style string) {
+	if style != pathStyleSlash {
+		return
+	}
+	for i := range issues {
+		issues[i].Pos.Filename = strings.ReplaceAll(filepath.ToSlash(issues[i].Pos.Filename), `\`, "/")
+	}
+}
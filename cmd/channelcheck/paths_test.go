@@ -0,0 +1,158 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestRelativizePaths(t *testing.T) {
+	root := t.TempDir()
+	inside := filepath.Join(root, "internal", "worker", "worker.go")
+	outside := filepath.Join(t.TempDir(), "other.go")
+
+	issues := []Issue{
+		{Pos: Position{Filename: inside}},
+		{Pos: Position{Filename: outside}},
+		{Pos: Position{Filename: outside}},
+	}
+
+	var warnings bytes.Buffer
+	if err := relativizePaths(issues, root, &warnings); err != nil {
+		t.Fatalf("relativizePaths failed: %v", err)
+	}
+
+	if want := filepath.Join("internal", "worker", "worker.go"); issues[0].Pos.Filename != want {
+		t.Errorf("got %q, want %q", issues[0].Pos.Filename, want)
+	}
+	if issues[1].Pos.Filename != outside {
+		t.Errorf("got %q, want the original path %q", issues[1].Pos.Filename, outside)
+	}
+	if got := strings.Count(warnings.String(), "is not inside root"); got != 1 {
+		t.Errorf("got %d warnings, want 1: %q", got, warnings.String())
+	}
+}
+
+func TestRun_Root(t *testing.T) {
+	root := t.TempDir()
+	writeFile(t, root, "pkg/sub/a.go", `package sub
+func send(ch chan int) {
+	ch <- 1
+}
+`)
+
+	var stdout, stderr bytes.Buffer
+	args := []string{"-path", filepath.Join(root, "pkg", "sub"), "-root", root, "-output", "json"}
+	if _, err := run(args, &stdout, &stderr); err != nil {
+		t.Fatalf("run failed: %v", err)
+	}
+
+	var output JSONOutput
+	if err := json.Unmarshal(stdout.Bytes(), &output); err != nil {
+		t.Fatalf("failed to parse output: %v", err)
+	}
+	if output.Total != 1 {
+		t.Fatalf("got %d issues, want 1", output.Total)
+	}
+	if want := filepath.Join("pkg", "sub", "a.go"); output.Issues[0].Position.Filename != want {
+		t.Errorf("got filename %q, want %q", output.Issues[0].Position.Filename, want)
+	}
+	if stderr.Len() != 0 {
+		t.Errorf("unexpected warnings: %q", stderr.String())
+	}
+}
+
+func TestRun_RelativeToModule(t *testing.T) {
+	dir := t.TempDir()
+	const src = `package test
+func send(ch chan int) {
+	ch <- 1
+}
+`
+	writeFile(t, dir, "repo/go.mod", "module example.com/repo\n")
+	writeFile(t, dir, "repo/internal/worker/worker.go", src)
+	writeFile(t, dir, "repo/tools/go.mod", "module example.com/repo/tools\n")
+	writeFile(t, dir, "repo/tools/gen.go", src)
+	writeFile(t, dir, "loose/main.go", src)
+
+	var stdout, stderr bytes.Buffer
+	if _, err := run([]string{"-path", dir, "-output", "json", "-relative-to-module"}, &stdout, &stderr); err != nil {
+		t.Fatalf("run failed: %v", err)
+	}
+
+	var output JSONOutput
+	if err := json.Unmarshal(stdout.Bytes(), &output); err != nil {
+		t.Fatalf("failed to parse output: %v", err)
+	}
+	var got []string
+	for _, issue := range output.Issues {
+		got = append(got, filepath.ToSlash(issue.Position.Filename))
+	}
+	want := []string{
+		filepath.ToSlash(filepath.Join(dir, "loose", "main.go")),
+		"internal/worker/worker.go",
+		"gen.go",
+	}
+	slices.Sort(got)
+	slices.Sort(want)
+	if !slices.Equal(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+	if !strings.Contains(stderr.String(), "is not inside a module") {
+		t.Errorf("expected a warning for the file outside a module, got %q", stderr.String())
+	}
+}
+
+func TestModuleRoots(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "go.mod", "module example.com/m\n")
+	deep := filepath.Join(dir, "a", "b", "c")
+
+	roots := make(moduleRoots)
+	if got := roots.find(deep); got != dir {
+		t.Errorf("got root %q, want %q", got, dir)
+	}
+	for _, d := range []string{deep, filepath.Join(dir, "a", "b"), filepath.Join(dir, "a"), dir} {
+		if root, ok := roots[d]; !ok || root != dir {
+			t.Errorf("lookup for %s not cached: %q, %v", d, root, ok)
+		}
+	}
+}
+
+func TestApplyPathStyle(t *testing.T) {
+	issues := []Issue{
+		{Pos: Position{Filename: `C:\src\repo\internal\worker.go`}},
+		{Pos: Position{Filename: "internal/api/api.go"}},
+	}
+
+	applyPathStyle(issues, pathStyleOS)
+	if got := issues[0].Pos.Filename; got != `C:\src\repo\internal\worker.go` {
+		t.Errorf("os style changed the path to %q", got)
+	}
+
+	applyPathStyle(issues, pathStyleSlash)
+	for i, want := range []string{"C:/src/repo/internal/worker.go", "internal/api/api.go"} {
+		if got := issues[i].Pos.Filename; got != want {
+			t.Errorf("issue %d: got %q, want %q", i, got, want)
+		}
+	}
+}
+
+func TestRun_PathStyle(t *testing.T) {
+	report := writeFile(t, t.TempDir(), "report.json", `{"issues": [{"severity": "WARNING", "message": "m", "position": {"filename": "pkg\\worker.go", "start_line": 3}}], "total": 1}`)
+
+	var stdout bytes.Buffer
+	if _, err := run([]string{"-render-from", report, "-output", "vim", "-path-style", "slash"}, &stdout, &bytes.Buffer{}); err != nil {
+		t.Fatalf("run failed: %v", err)
+	}
+	if got := stdout.String(); !strings.HasPrefix(got, "pkg/worker.go:3:") {
+		t.Errorf("got %q, want a slash-separated path", got)
+	}
+
+	if _, err := run([]string{"-path", ".", "-path-style", "dos"}, &bytes.Buffer{}, &bytes.Buffer{}); err == nil {
+		t.Errorf("expected an error for an invalid -path-style")
+	}
+}
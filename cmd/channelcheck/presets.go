@@ -0,0 +1,125 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// ruleConfig selects which rules report issues and at what severity.
+type ruleConfig struct {
+	// enabled overrides whether a rule reports issues. Rules missing from
+	// the map fall back to the registry: enabled unless OptIn.
+	enabled map[string]bool
+	// severity overrides the severity a rule reports issues at.
+	severity map[string]string
+}
+
+// presetNames lists the values accepted by -preset.
+var presetNames = []string{"default", "all", "strict", "minimal"}
+
+// minimalRules is the core subset enabled by the minimal preset: findings
+// that are almost always real bugs rather than style.
+var minimalRules = []string{
+	RuleSendWithoutSelect,
+	RuleSendAfterClose,
+	RuleConditionalMake,
+	RuleProducerClose,
+}
+
+// presetConfig resolves a preset into a ruleConfig covering every rule in
+// the registry.
+//
+//   - default: the rules enabled by default, at their registry severity.
+//   - all: every rule, including opt-in ones.
+//   - strict: every rule, each promoted one severity level.
+//   - minimal: only minimalRules.
+func presetConfig(name string) (ruleConfig, error) {
+	if !slices.Contains(presetNames, name) {
+		return ruleConfig{}, fmt.Errorf("invalid -preset value %q: valid options are %s", name, strings.Join(presetNames, ", "))
+	}
+
+	config := ruleConfig{
+		enabled:  make(map[string]bool, len(rules)),
+		severity: make(map[string]string),
+	}
+	for _, rule := range rules {
+		switch name {
+		case "default":
+			config.enabled[rule.ID] = !rule.OptIn
+		case "all":
+			config.enabled[rule.ID] = true
+		case "strict":
+			config.enabled[rule.ID] = true
+			config.severity[rule.ID] = promoteSeverity(rule.Severity)
+		case "minimal":
+			config.enabled[rule.ID] = slices.Contains(minimalRules, rule.ID)
+		}
+	}
+	return config, nil
+}
+
+// promoteSeverity returns the next severity up, capped at ERROR.
+func promoteSeverity(severity string) string {
+	switch severity {
+	case "INFO":
+		return "WARNING"
+	default:
+		return "ERROR"
+	}
+}
+
+// setEnabled applies a comma-separated -enable or -disable list of rule IDs.
+func (c ruleConfig) setEnabled(list string, enabled bool) error {
+	if list == "" {
+		return nil
+	}
+	for _, id := range strings.Split(list, ",") {
+		id = strings.TrimSpace(id)
+		if !slices.ContainsFunc(rules, func(rule Rule) bool { return rule.ID == id }) {
+			return fmt.Errorf("unknown rule %q", id)
+		}
+		c.enabled[id] = enabled
+	}
+	return nil
+}
+
+// setSeverity overrides the severity of the given rule IDs.
+func (c ruleConfig) setSeverity(ids []string, severity string) error {
+	for _, id := range ids {
+		if !slices.ContainsFunc(rules, func(rule Rule) bool { return rule.ID == id }) {
+			return fmt.Errorf("unknown rule %q", id)
+		}
+		c.severity[id] = severity
+	}
+	return nil
+}
+
+// ruleList collects rule IDs from a flag that may be repeated, each use
+// listing one or more comma-separated IDs.
+type ruleList []string
+
+func (l *ruleList) String() string {
+	return strings.Join(*l, ",")
+}
+
+func (l *ruleList) Set(value string) error {
+	for _, id := range strings.Split(value, ",") {
+		*l = append(*l, strings.TrimSpace(id))
+	}
+	return nil
+}
+
+// ruleEnabled reports whether issues from the rule should be reported.
+// Issues without a known rule are always reported.
+func (a *Analyzer) ruleEnabled(id string) bool {
+	if enabled, ok := a.config.enabled[id]; ok {
+		return enabled
+	}
+	for _, rule := range rules {
+		if rule.ID == id {
+			return !rule.OptIn
+		}
+	}
+	return true
+}
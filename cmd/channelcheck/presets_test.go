@@ -0,0 +1,100 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"go/token"
+	"slices"
+	"testing"
+)
+
+func TestPresetConfig(t *testing.T) {
+	strict, err := presetConfig("strict")
+	if err != nil {
+		t.Fatalf("presetConfig failed: %v", err)
+	}
+	for _, rule := range rules {
+		if !strict.enabled[rule.ID] {
+			t.Errorf("strict: rule %s is not enabled", rule.ID)
+		}
+		if got, want := severityRank(strict.severity[rule.ID]), min(severityRank(rule.Severity)+1, severityRank("ERROR")); got != want {
+			t.Errorf("strict: rule %s has severity %s, want it promoted from %s", rule.ID, strict.severity[rule.ID], rule.Severity)
+		}
+	}
+
+	minimal, err := presetConfig("minimal")
+	if err != nil {
+		t.Fatalf("presetConfig failed: %v", err)
+	}
+	for _, rule := range rules {
+		if got, want := minimal.enabled[rule.ID], slices.Contains(minimalRules, rule.ID); got != want {
+			t.Errorf("minimal: rule %s enabled = %v, want %v", rule.ID, got, want)
+		}
+	}
+	if len(minimal.severity) != 0 {
+		t.Errorf("minimal: unexpected severity overrides %v", minimal.severity)
+	}
+
+	if _, err := presetConfig("loud"); err == nil {
+		t.Errorf("expected an error for an unknown preset")
+	}
+}
+
+func TestAnalyzer_RuleConfig(t *testing.T) {
+	const code = `package test
+func send() {
+	ch := make(chan int)
+	ch <- 1
+}
+`
+	analyze := func(config ruleConfig) []Issue {
+		t.Helper()
+		analyzer := &Analyzer{fset: token.NewFileSet(), config: config}
+		if err := analyzer.analyzeSource("test.go", []byte(code)); err != nil {
+			t.Fatalf("analysis failed: %v", err)
+		}
+		return analyzer.issues
+	}
+
+	strict, _ := presetConfig("strict")
+	for _, issue := range analyze(strict) {
+		want := map[string]string{RuleUnbufferedChannel: "WARNING", RuleSendWithoutSelect: "ERROR"}[issue.Rule]
+		if issue.Severity != want {
+			t.Errorf("strict: %s reported at %s, want %s", issue.Rule, issue.Severity, want)
+		}
+	}
+
+	minimal, _ := presetConfig("minimal")
+	issues := analyze(minimal)
+	if len(issues) != 1 || issues[0].Rule != RuleSendWithoutSelect {
+		t.Errorf("minimal: got %s, want only %s", formatIssues(issues), RuleSendWithoutSelect)
+	}
+}
+
+func TestRun_PresetOverrides(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "a.go", `package test
+func send() {
+	ch := make(chan int)
+	ch <- 1
+}
+`)
+
+	var stdout bytes.Buffer
+	args := []string{"-path", dir, "-output", "json", "-preset", "minimal", "-enable", RuleUnbufferedChannel, "-disable", RuleSendWithoutSelect}
+	if _, err := run(args, &stdout, &bytes.Buffer{}); err != nil {
+		t.Fatalf("run failed: %v", err)
+	}
+
+	var output JSONOutput
+	if err := json.Unmarshal(stdout.Bytes(), &output); err != nil {
+		t.Fatalf("failed to parse output: %v", err)
+	}
+	if output.Total != 1 || output.Issues[0].Rule != RuleUnbufferedChannel {
+		t.Errorf("got %+v, want only %s", output.Issues, RuleUnbufferedChannel)
+	}
+
+	if _, err := run([]string{"-path", dir, "-enable", "no-such-rule"}, &bytes.Buffer{}, &bytes.Buffer{}); err == nil {
+		t.Errorf("expected an error for an unknown rule")
+	}
+}
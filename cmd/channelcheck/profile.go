@@ -0,0 +1,50 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"runtime"
+	"runtime/pprof"
+)
+
+// startProfiling starts a CPU profile written to cpuFile, if set. The
+// returned function stops it and, if memFile is set, writes a heap profile
+// there; call it once the run is done.
+func startProfiling(cpuFile, memFile string) (func() error, error) {
+	var cpu *os.File
+	if cpuFile != "" {
+		var err error
+		if cpu, err = os.Create(cpuFile); err != nil {
+			return nil, fmt.Errorf("error creating CPU profile: %w", err)
+		}
+		if err := pprof.StartCPUProfile(cpu); err != nil {
+			return nil, errors.Join(fmt.Errorf("error starting CPU profile: %w", err), cpu.Close())
+		}
+	}
+
+	return func() error {
+		var errs []error
+		if cpu != nil {
+			pprof.StopCPUProfile()
+			errs = append(errs, cpu.Close())
+		}
+		if memFile != "" {
+			errs = append(errs, writeHeapProfile(memFile))
+		}
+		return errors.Join(errs...)
+	}, nil
+}
+
+func writeHeapProfile(path string) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("error creating memory profile: %w", err)
+	}
+	// Collect garbage first so the profile shows live memory.
+	runtime.GC()
+	if err := pprof.WriteHeapProfile(f); err != nil {
+		return errors.Join(fmt.Errorf("error writing memory profile: %w", err), f.Close())
+	}
+	return f.Close()
+}
@@ -0,0 +1,599 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Rule IDs identify the check that reported an issue.
+const (
+	RuleSendWithoutSelect      = "send-without-select"
+	RuleUnbufferedChannel      = "unbuffered-channel"
+	RuleTimeAfterInLoop        = "time-after-in-loop"
+	RuleSendUnderLock          = "send-under-lock"
+	RuleSpinningDrain          = "spinning-drain"
+	RuleLargeBuffer            = "large-buffer"
+	RuleIndexedSend            = "indexed-send"
+	RuleBusyRetry              = "busy-retry"
+	RuleSendAfterClose         = "send-after-close"
+	RuleReceiveInLoopCond      = "receive-in-loop-cond"
+	RuleSingleCaseSelect       = "single-case-select"
+	RuleConditionalMake        = "conditional-make"
+	RuleProducerClose          = "producer-close"
+	RuleSilentDrop             = "silent-drop"
+	RuleInvertedCommaOk        = "inverted-comma-ok"
+	RuleUnreachableChannel     = "unreachable-channel"
+	RuleSendInRecover          = "send-in-recover"
+	RuleLogOnlyDefault         = "log-only-default"
+	RuleRangeSignal            = "range-signal"
+	RuleReturnedUnbuffered     = "returned-unbuffered"
+	RuleUncoordinatedFanIn     = "uncoordinated-fan-in"
+	RuleUnusedReceive          = "unused-receive"
+	RuleCapturedReassignedChan = "captured-reassigned-chan"
+	RuleNilCheckFallthrough    = "nil-check-fallthrough"
+	RuleUnusedChanParam        = "unused-chan-param"
+	RuleSendContainsChan       = "send-contains-chan"
+	RuleSiblingClose           = "sibling-close"
+	RuleReaderLeak             = "reader-leak"
+	RuleFuncChan               = "func-chan"
+	RuleNilReceiverSend        = "nil-receiver-send"
+	RuleLenBuffer              = "len-buffer"
+	RuleContextChan            = "context-chan"
+	RuleMainDeadlock           = "main-deadlock"
+	RuleAtomicMix              = "atomic-mix"
+	RuleLoopVarCapture         = "loop-var-capture"
+	RuleUnusedSuppression      = "unused-suppression"
+	RuleOverCapacity           = "over-capacity"
+	RuleUnreadErrors           = "unread-errors"
+	RuleNilFieldSend           = "nil-field-send"
+	RuleDropHandler            = "drop-handler"
+	RuleHandlerSend            = "handler-send"
+	RuleParseError             = "parse-error"
+	RuleChanAsSlice            = "chan-as-slice"
+	RuleClosedRecvOperand      = "closed-recv-operand"
+	RuleOnceBlocking           = "once-blocking"
+	RuleImmediateClose         = "immediate-close"
+	RulePackageChan            = "package-chan"
+	RuleUnawaitedReply         = "unawaited-reply"
+	RuleSendBeforeGo           = "send-before-go"
+	RuleChanOfChan             = "chan-of-chan"
+	RuleShadowedCaseSend       = "shadowed-case-send"
+)
+
+// ruleDocBase is where each rule is documented, under a heading named after
+// its ID.
+const ruleDocBase = "https://github.com/johnsaigle/channelcheck#"
+
+// Rule describes a check.
+type Rule struct {
+	ID          string `json:"id"`
+	Title       string `json:"title"`
+	Severity    string `json:"severity"`
+	Description string `json:"description"`
+	DocURL      string `json:"doc_url"`
+	// Rationale explains at length why the issue matters. -explain prints
+	// it under each issue in text output.
+	Rationale string `json:"rationale,omitempty"`
+	// Fixable is set for rules that can rewrite the code with -fix.
+	Fixable bool `json:"fixable"`
+	// OptIn is set for rules that only run when enabled explicitly, by
+	// -enable or the all and strict presets.
+	OptIn bool `json:"opt_in"`
+}
+
+// rules is the registry of every built-in check.
+var rules = []Rule{
+	{
+		ID:          RuleSendWithoutSelect,
+		Title:       "Channel send without select",
+		Severity:    "WARNING",
+		Description: "A plain send blocks until a receiver is ready and can block forever if none arrives.",
+		Rationale: "An unbuffered send completes only when a receiver takes the value, and a buffered one\n" +
+			"blocks once the buffer is full. If the receiver has returned, crashed or is itself waiting\n" +
+			"on the sender, the goroutine blocks forever and everything it holds leaks with it.\n" +
+			"Wrapping the send in a select with a ctx.Done() or timeout case gives it a way out.",
+	},
+	{
+		ID:          RuleUnbufferedChannel,
+		Title:       "Unbuffered channel creation",
+		Severity:    "INFO",
+		Description: "Unbuffered channels synchronize every send with a receive, a common source of deadlocks.",
+		Rationale: "Every send on an unbuffered channel waits for a matching receive, so sender and\n" +
+			"receiver run in lockstep. That is sometimes the point, but when one side stops early or\n" +
+			"both wait on each other the program deadlocks. Choose the capacity deliberately.",
+		Fixable: true,
+	},
+	{
+		ID:          RuleTimeAfterInLoop,
+		Title:       "time.After in a loop select",
+		Severity:    "INFO",
+		Description: "time.After creates a new timer on every iteration, so the timeout restarts instead of bounding the whole loop.",
+		Rationale: "time.After starts a fresh timer each time the select is evaluated, so a loop that\n" +
+			"keeps receiving other cases never reaches the timeout, and every abandoned timer stays\n" +
+			"allocated until it fires. Create one time.Timer or context deadline outside the loop.",
+	},
+	{
+		ID:          RuleSendUnderLock,
+		Title:       "Channel send while holding a lock",
+		Severity:    "WARNING",
+		Description: "Sending while holding a mutex deadlocks if the receiver needs the same mutex.",
+		Rationale: "While the mutex is held, the send waits for a receiver. If that receiver, or anything it\n" +
+			"waits on, needs the same mutex, neither side can proceed. Even without a deadlock, every\n" +
+			"other user of the lock stalls for as long as the receiver takes. Send after unlocking.",
+	},
+	{
+		ID:          RuleSpinningDrain,
+		Title:       "Drain loop spins after close",
+		Severity:    "WARNING",
+		Description: "for { <-ch } spins forever once ch is closed because receives return the zero value immediately.",
+		Rationale: "A receive from a closed channel returns the zero value immediately, so for { <-ch }\n" +
+			"turns into a busy loop that burns a CPU once the channel is closed. Use for range ch,\n" +
+			"or check the second value of v, ok := <-ch and stop when ok is false.",
+	},
+	{
+		ID:          RuleLargeBuffer,
+		Title:       "Large channel buffer",
+		Severity:    "INFO",
+		Description: "A buffered channel reserves capacity times element size bytes up front.",
+		Rationale: "make allocates the whole buffer up front, capacity times element size, whether or not\n" +
+			"it is ever filled. A large buffer also hides backpressure: producers run far ahead of\n" +
+			"consumers and problems show up as memory growth instead of blocked senders.",
+	},
+	{
+		ID:          RuleIndexedSend,
+		Title:       "Send on a channel from a collection",
+		Severity:    "INFO",
+		Description: "A missing map entry or unset slice element is a nil channel, and the collection may need synchronization.",
+		Rationale: "Indexing a map with a missing key, or a slice element that was never set, yields a nil\n" +
+			"channel, and a send on a nil channel blocks forever. Concurrent access to the map itself\n" +
+			"may also need a lock. Look the channel up once, check it and send on the local.",
+	},
+	{
+		ID:          RuleBusyRetry,
+		Title:       "Busy retry of a channel send",
+		Severity:    "WARNING",
+		Description: "Retrying a non-blocking send in a loop with an empty default pins a CPU until a receiver is ready.",
+		Rationale: "A select with a send and an empty default returns immediately when nobody is ready, so\n" +
+			"retrying it in a loop spins a CPU at full speed until a receiver shows up. Block on the\n" +
+			"send with a timeout or context case instead, or back off between attempts.",
+	},
+	{
+		ID:          RuleSendAfterClose,
+		Title:       "Send after a helper closes the channel",
+		Severity:    "WARNING",
+		Description: "Sending on a channel that a previously called function closed panics.",
+		Rationale: "Sending on a closed channel panics. Once a function that closes the channel has run,\n" +
+			"every later send on it is a crash waiting for the right code path.",
+	},
+	{
+		ID:          RuleReceiveInLoopCond,
+		Title:       "Receive in for-loop condition",
+		Severity:    "INFO",
+		Description: "A receive in a loop condition blocks before every iteration.",
+		Rationale: "The receive in the loop condition runs before every iteration, blocking the loop on\n" +
+			"the channel and consuming a value each time. This is easy to misread as a cheap check.\n" +
+			"Receive in the body, or range over the channel, to make the blocking explicit.",
+	},
+	{
+		ID:          RuleSingleCaseSelect,
+		Title:       "Single-case select",
+		Severity:    "INFO",
+		Description: "A select with one case and no default behaves exactly like the plain channel operation.",
+		Rationale: "A select with one case and no default blocks exactly like the bare channel operation,\n" +
+			"so it adds indentation without adding behaviour. It usually means a timeout, context or\n" +
+			"default case was intended and forgotten.",
+	},
+	{
+		ID:          RuleConditionalMake,
+		Title:       "Channel made only on some paths",
+		Severity:    "WARNING",
+		Description: "If the make only runs inside a branch, the channel is nil on the other paths and the send blocks forever.",
+		Rationale: "A channel declared with var is nil until made. If the make only runs on some paths, the\n" +
+			"other paths reach the send with a nil channel, and that send blocks forever without an\n" +
+			"error. Make the channel unconditionally or return before sending.",
+	},
+	{
+		ID:          RuleProducerClose,
+		Title:       "Close races with other producers",
+		Severity:    "WARNING",
+		Description: "When several goroutines send on a channel, one of them closing it can race with the others' sends and panic.",
+		Rationale: "Only the last sender may close a channel. When several goroutines send and one of them\n" +
+			"closes it, the others can still be mid-send and panic. Close from a single owner after a\n" +
+			"sync.WaitGroup reports that every producer is done.",
+	},
+	{
+		ID:          RuleSilentDrop,
+		Title:       "Silently dropped non-blocking send",
+		Severity:    "INFO",
+		Description: "A non-blocking send on an unbuffered channel drops the value unless a receiver is waiting at that instant.",
+		Rationale: "An unbuffered channel only accepts a value when a receiver is waiting at that exact\n" +
+			"moment, so a non-blocking send on it drops almost everything under load. Buffer the\n" +
+			"channel or make the send blocking with a timeout.",
+	},
+	{
+		ID:          RuleInvertedCommaOk,
+		Title:       "Value used after a failed comma-ok receive",
+		Severity:    "INFO",
+		Description: "Inside `if v, ok := <-ch; !ok` the channel is closed and v is only the zero value.",
+		Rationale: "ok is false only when the channel is closed and drained, and then v is the zero value.\n" +
+			"Code in the !ok branch that uses v is handling a value that was never sent.",
+	},
+	{
+		ID:          RuleUnreachableChannel,
+		Title:       "Channel used only in unreachable code",
+		Severity:    "INFO",
+		Description: "A channel whose only uses are inside an `if false` block is dead code.",
+		Rationale: "Code inside if false never runs, so a channel used only there is never created, sent on\n" +
+			"or received from. It is usually left over from debugging and should be removed.",
+	},
+	{
+		ID:          RuleSendInRecover,
+		Title:       "Channel send in panic recovery",
+		Severity:    "WARNING",
+		Description: "A deferred function that recovers and then sends can block if nothing reads the channel after the panic.",
+		Rationale: "After a panic is recovered, the code that would have received from the channel may be\n" +
+			"gone or already returned. A send in the deferred function then blocks forever and turns\n" +
+			"a recovered panic into a leaked goroutine. Use a buffered channel or a select.",
+	},
+	{
+		ID:          RuleLogOnlyDefault,
+		Title:       "Select default that only logs",
+		Severity:    "INFO",
+		Description: "A select default that only logs dropped work leaves capacity problems out of metrics.",
+		Rationale: "A default that only logs makes dropped work visible in logs but not in metrics or\n" +
+			"alerts, so a sustained overload is easy to miss. Count the drop where it can be graphed.",
+		OptIn: true,
+	},
+	{
+		ID:          RuleRangeSignal,
+		Title:       "Range over a close-signal channel",
+		Severity:    "INFO",
+		Description: "A chan struct{} that is only closed never yields values, so ranging over it is an indirect way to await the close.",
+		Rationale: "A chan struct{} that is only ever closed carries no values, so a range over it runs zero\n" +
+			"iterations and simply waits for the close. <-done says the same thing directly.",
+	},
+	{
+		ID:          RuleReturnedUnbuffered,
+		Title:       "Exported function returns an unbuffered channel",
+		Severity:    "INFO",
+		Description: "Library callers cannot see whether a returned channel is buffered; document it or buffer the channel.",
+		Rationale: "Callers of an exported function cannot see whether the channel it returns is buffered.\n" +
+			"If it is unbuffered, a caller that stops receiving blocks the producer forever. Document\n" +
+			"the capacity and who closes the channel, or buffer it.",
+		OptIn: true,
+	},
+	{
+		ID:          RuleUncoordinatedFanIn,
+		Title:       "Fan-in without close coordination",
+		Severity:    "INFO",
+		Description: "Several unbuffered channels fed by goroutines and never closed give the merging side no way to know the producers are done.",
+		Rationale: "Merging several channels needs to know when every producer is done. If the channels\n" +
+			"are unbuffered and never closed, the merging side cannot tell finished producers from\n" +
+			"slow ones, and it either leaks or stops early. Close each channel when its producer ends.",
+	},
+	{
+		ID:          RuleUnusedReceive,
+		Title:       "Received value never used",
+		Severity:    "INFO",
+		Description: "A received value that is assigned but never read was either meant to be used or should be a bare receive.",
+		Rationale: "Assigning a received value and never reading it usually means the value was meant to\n" +
+			"be used. If only the synchronization matters, a bare <-ch says so.",
+	},
+	{
+		ID:          RuleCapturedReassignedChan,
+		Title:       "Goroutine captures a reassigned channel variable",
+		Severity:    "WARNING",
+		Description: "Goroutines that capture a channel variable the loop reassigns share it and may send on a later iteration's channel.",
+		Rationale: "A goroutine that captures a variable, rather than its value, sees later assignments to\n" +
+			"it. When the loop reassigns the channel, goroutines from earlier iterations send on a\n" +
+			"later iteration's channel. Pass the channel as an argument to the goroutine.",
+	},
+	{
+		ID:          RuleNilCheckFallthrough,
+		Title:       "Send after a nil check that falls through",
+		Severity:    "WARNING",
+		Description: "An `if ch == nil` branch that neither returns nor assigns ch lets a nil channel reach the send, which then blocks forever.",
+		Rationale: "A nil check that neither returns nor assigns the channel does nothing to protect the\n" +
+			"send that follows it, and a send on a nil channel blocks forever. Return, make the\n" +
+			"channel, or skip the send inside the branch.",
+	},
+	{
+		ID:          RuleUnusedChanParam,
+		Title:       "Goroutine ignores its channel parameter",
+		Severity:    "INFO",
+		Description: "A function started as a goroutine that never uses a channel it is given suggests the wrong channel was wired up.",
+		Rationale: "A goroutine that is handed a channel and never touches it cannot signal or be\n" +
+			"signalled through it, so whoever waits on that channel waits forever. It usually means\n" +
+			"the wrong channel was passed or the goroutine lost its send.",
+	},
+	{
+		ID:          RuleSendContainsChan,
+		Title:       "Sending a value that contains channels",
+		Severity:    "INFO",
+		Description: "Sending a struct holding channels shares them between sender and receiver, blurring which side owns and closes them.",
+		Rationale: "A value sent with channels inside it gives both sides a reference to those channels,\n" +
+			"so ownership, and with it the responsibility to close, becomes unclear. Document the\n" +
+			"ownership or send the channels separately.",
+	},
+	{
+		ID:          RuleSiblingClose,
+		Title:       "Select sends on a channel a sibling case closes",
+		Severity:    "WARNING",
+		Description: "A select that sends on a channel in one case and closes it in another panics if the send case runs after the close.",
+		Rationale: "Select picks among ready cases at random. If one case closes the channel and another\n" +
+			"sends on it, a later iteration can run the send after the close and panic.",
+	},
+	{
+		ID:          RuleReaderLeak,
+		Title:       "Reader goroutine without cancellation",
+		Severity:    "WARNING",
+		Description: "A goroutine that blocks on a plain receive, with no select, context or close to end it, leaks once its senders stop.",
+		Rationale: "A goroutine parked on a bare receive only wakes up on a value or a close. If the\n" +
+			"senders stop without closing, it never wakes and leaks its stack and everything it\n" +
+			"references. Select on ctx.Done() as well, or make sure the channel is closed.",
+	},
+	{
+		ID:          RuleFuncChan,
+		Title:       "Channel of functions",
+		Severity:    "INFO",
+		Description: "A goroutine running callbacks from a chan func() stops draining it while a handler blocks, which can deadlock senders.",
+		Rationale: "A goroutine that runs every func it receives cannot receive the next one while a\n" +
+			"handler is running. A handler that blocks, or sends back on the same channel, stalls\n" +
+			"every sender behind it.",
+		OptIn: true,
+	},
+	{
+		ID:          RuleNilReceiverSend,
+		Title:       "Send on a field of an unchecked pointer receiver",
+		Severity:    "INFO",
+		Description: "A method that sends on a channel field of its pointer receiver without checking it for nil panics when called on a nil pointer.",
+		Rationale: "Methods on pointer receivers can be called on a nil pointer. Reading a channel field\n" +
+			"through it panics with a nil dereference. Check the receiver before sending.",
+	},
+	{
+		ID:          RuleLenBuffer,
+		Title:       "Channel buffered to the size of its whole input",
+		Severity:    "INFO",
+		Description: "Buffering a channel by len(items) and sending every item queues the whole input in memory; a bounded worker pool keeps backpressure.",
+		Rationale: "Sizing the buffer by the input means all of the input is queued at once, so memory grows\n" +
+			"with the input and consumers get no backpressure. A fixed-size pool of workers reading\n" +
+			"from a small buffer keeps memory bounded.",
+		OptIn: true,
+	},
+	{
+		ID:          RuleContextChan,
+		Title:       "Channel stored in a context",
+		Severity:    "INFO",
+		Description: "A channel passed around as a context value hides who sends on, receives from and closes it.",
+		Rationale: "Context values are untyped and invisible in signatures, so a channel passed that way\n" +
+			"has no clear owner: readers cannot tell who sends on it or who closes it. Pass the\n" +
+			"channel as a parameter instead.",
+	},
+	{
+		ID:          RuleMainDeadlock,
+		Title:       "Blocking receive in main without goroutines",
+		Severity:    "INFO",
+		Description: "A bare receive in func main of a file that starts no goroutines has nothing to send to it, so the program deadlocks.",
+		Rationale: "With no goroutines running, nothing can ever send on the channel, so the receive in\n" +
+			"main blocks forever and the runtime aborts with \"all goroutines are asleep\".",
+	},
+	{
+		ID:          RuleAtomicMix,
+		Title:       "Channels mixed with atomics",
+		Severity:    "INFO",
+		Description: "A function that coordinates through both channel operations and sync/atomic calls splits its synchronization across two mechanisms.",
+		Rationale: "Synchronizing through both channels and sync/atomic means the happens-before edges\n" +
+			"are split across two mechanisms, which makes the ordering hard to reason about. Pick one\n" +
+			"for the state a function coordinates.",
+		OptIn: true,
+	},
+	{
+		ID:          RuleLoopVarCapture,
+		Title:       "Goroutine captures a loop variable",
+		Severity:    "WARNING",
+		Description: "Before Go 1.22, a goroutine started in a loop that refers to a loop variable sees the shared variable, not the iteration's value. Only checked when -go selects an older version.",
+		Rationale: "Before Go 1.22 a loop variable is a single variable shared by every iteration. A\n" +
+			"goroutine that refers to it usually sees the last value, not the one from its iteration.\n" +
+			"Pass the variable as an argument or copy it inside the loop.",
+	},
+	{
+		ID:          RuleUnusedSuppression,
+		Title:       "Suppression comment without an issue",
+		Severity:    "WARNING",
+		Description: "A suppression comment that no longer suppresses anything hides future issues on its line. Only checked with -report-unused-suppressions.",
+		Rationale: "A suppression that no longer matches anything still silences its line, so a real issue\n" +
+			"introduced there later goes unreported. Remove the comment once it is no longer needed.",
+	},
+	{
+		ID:          RuleOverCapacity,
+		Title:       "More sends than channel capacity",
+		Severity:    "ERROR",
+		Description: "A function that sends on a channel it made with a constant capacity more times in a row than the capacity, with no receiver able to run in between, blocks forever.",
+		Rationale: "Each send beyond the buffer's capacity waits for a receiver. If no receiver can run\n" +
+			"between the sends in the same function, the goroutine blocks on the first extra send\n" +
+			"forever.",
+	},
+	{
+		ID:          RuleUnreadErrors,
+		Title:       "Error channel that is never read",
+		Severity:    "WARNING",
+		Description: "Errors sent on a chan error that the function never receives from, returns or passes on are silently lost.",
+		Rationale: "An error sent on a channel nobody reads is lost, and with an unbuffered or full channel\n" +
+			"the sending goroutine also blocks. Receive the errors, return the channel or pass it on.",
+	},
+	{
+		ID:          RuleNilFieldSend,
+		Title:       "Send on a channel field left nil",
+		Severity:    "WARNING",
+		Description: "A struct literal that leaves a channel field nil, followed by a send on that field with no make in between, blocks forever.",
+		Rationale: "A channel field left out of a struct literal is nil, and a send on a nil channel blocks\n" +
+			"forever without an error. Make the channel in the literal or in a constructor.",
+	},
+	{
+		ID:          RuleDropHandler,
+		Title:       "Non-blocking send without a drop handler",
+		Severity:    "WARNING",
+		Description: "The default of a non-blocking send must call one of the -drop-handler functions so dropped values are observable. Only checked when -drop-handler is set.",
+		Rationale: "A non-blocking send drops the value whenever the receiver is not ready. Calling the\n" +
+			"team's drop handler in the default makes those drops visible in metrics, so overload\n" +
+			"shows up before it becomes data loss.",
+	},
+	{
+		ID:          RuleHandlerSend,
+		Title:       "Blocking send in an HTTP handler",
+		Severity:    "WARNING",
+		Description: "A send in an HTTP handler that does not also select on the request context's Done channel keeps the request goroutine waiting after the client has gone.",
+		Rationale: "net/http runs each request on its own goroutine and cancels r.Context() when the client\n" +
+			"disconnects. A send that ignores that cancellation keeps the goroutine, the connection\n" +
+			"and everything the handler holds alive until a receiver shows up. Select on the send and\n" +
+			"on r.Context().Done() so the handler returns with the request.",
+	},
+	{
+		ID:          RuleParseError,
+		Title:       "File does not parse",
+		Severity:    "ERROR",
+		Description: "A file with syntax errors cannot be analyzed, so none of its issues are reported. It does not fail the run unless -fail-on-parse-error is set.",
+		Rationale: "channelcheck keeps going when a file has syntax errors so one broken file does not hide\n" +
+			"the findings in the rest of the tree. Nothing in the broken file is checked, though, and\n" +
+			"its channel bugs stay invisible until it parses. Use -fail-on-parse-error to catch this in CI.",
+	},
+	{
+		ID:          RuleChanAsSlice,
+		Title:       "Channel used as a slice",
+		Severity:    "INFO",
+		Description: "A channel made with capacity N, filled by exactly N sends, closed and ranged over with no goroutines involved is a slice in disguise.",
+		Rationale: "Nothing runs concurrently with the sends, so the channel only stores values in order.\n" +
+			"A slice does the same with less machinery, and readers do not have to look for the\n" +
+			"goroutine that the channel suggests.",
+		OptIn: true,
+	},
+	{
+		ID:          RuleClosedRecvOperand,
+		Title:       "Receive used as a divisor or index",
+		Severity:    "INFO",
+		Description: "A receive used directly as a divisor or index yields 0 once the channel is closed, dividing by zero or selecting the first element.",
+		Rationale: "A receive from a closed channel returns the zero value immediately. Used as a divisor it\n" +
+			"panics with an integer divide by zero; used as an index it quietly picks element 0, or\n" +
+			"panics on an empty slice. Receive with v, ok := <-ch first and handle !ok.",
+	},
+	{
+		ID:          RuleOnceBlocking,
+		Title:       "Blocking channel operation in sync.Once.Do",
+		Severity:    "WARNING",
+		Description: "A send or receive outside a select in a sync.Once.Do function can block Do forever, and every other caller of Do waits with it.",
+		Rationale: "sync.Once holds a mutex while the function passed to Do runs, and every concurrent or\n" +
+			"later call to Do waits on that mutex until the first call returns. If the function blocks\n" +
+			"on a channel, all of those callers block with it. Do the channel work outside Do, or\n" +
+			"select with a timeout or context case.",
+	},
+	{
+		ID:          RuleImmediateClose,
+		Title:       "Channel closed right after creation",
+		Severity:    "INFO",
+		Description: "A channel closed on the statement after its make, with no sends on it, is a signal that has already fired.",
+		Rationale: "Closing a channel right after making it gives a signal that is already given: every\n" +
+			"receive returns at once. That is sometimes deliberate, for a stub or an always-ready\n" +
+			"default, but code that later waits on the channel usually expects the signal to come\n" +
+			"later. If the close is intended, a comment saves the next reader the question.",
+	},
+	{
+		ID:          RulePackageChan,
+		Title:       "Package-level channel",
+		Severity:    "INFO",
+		Description: "A send or receive on a package-level channel variable, which any function in the package can also send on, receive from or close.",
+		Rationale: "A package-level channel is shared state: every function in the package can send on it,\n" +
+			"receive from it or close it, and tests share it across cases. Nothing at the use says who\n" +
+			"owns it or when it closes. Prefer a channel owned by a struct or passed as a parameter, or\n" +
+			"document the lifecycle where the variable is declared.",
+	},
+	{
+		ID:          RuleUnawaitedReply,
+		Title:       "Reply channel never awaited",
+		Severity:    "INFO",
+		Description: "A request sent with an unbuffered reply channel the sender never receives from leaves the goroutine serving it blocked on its reply.",
+		Rationale: "In a request/reply exchange the server answers by sending on the reply channel carried\n" +
+			"in the request. If the requester never receives from it, and the channel is unbuffered,\n" +
+			"that send blocks forever and the server goroutine stops serving. Receive the reply, or\n" +
+			"give the reply channel a buffer of 1 if the answer may be ignored.",
+	},
+	{
+		ID:          RuleSendBeforeGo,
+		Title:       "Send before its consumer starts",
+		Severity:    "ERROR",
+		Description: "A send on a local unbuffered channel followed by the go statement that receives from it blocks before the receiver exists.",
+		Rationale: "An unbuffered send waits for a receiver. When the only receiver is a goroutine started\n" +
+			"on a later line, the sending goroutine never gets to that line, and the program\n" +
+			"deadlocks. Start the consumer first, or buffer the channel.",
+	},
+	{
+		ID:          RuleChanOfChan,
+		Title:       "Channel of channels",
+		Severity:    "INFO",
+		Description: "A channel of channels, the usual request/reply shape, leaves the ownership of the inner channels undocumented in the type.",
+		Rationale: "With chan chan T, the requester makes the inner channel and the server sends on it, but\n" +
+			"nothing in the type says who closes it, whether it is buffered, or whether the requester\n" +
+			"is guaranteed to wait for the reply. Document the protocol where the channel is made, or\n" +
+			"wrap the inner channel in a request struct that names its role.",
+		OptIn: true,
+	},
+	{
+		ID:          RuleShadowedCaseSend,
+		Title:       "Send on a variable shadowed by a select case",
+		Severity:    "INFO",
+		Description: "A send in a select case on a variable the case's receive declared, hiding an outer variable of the same name, goes to the received value rather than the outer channel.",
+		Rationale: "case ch := <-in declares a new ch for the body of the case, hiding any outer ch. A send\n" +
+			"on ch in that body goes to the value just received, which is easy to misread as the\n" +
+			"outer channel. Rename the received value so each name refers to one channel.",
+	},
+}
+
+func init() {
+	for i := range rules {
+		rules[i].DocURL = ruleDocBase + rules[i].ID
+	}
+}
+
+// writeRationale writes the rationale of the rule with the given ID, each
+// line indented, followed by a blank line. It writes nothing for rules
+// without one.
+func writeRationale(w io.Writer, id string) error {
+	for _, rule := range rules {
+		if rule.ID != id || rule.Rationale == "" {
+			continue
+		}
+		for _, line := range strings.Split(rule.Rationale, "\n") {
+			if _, err := fmt.Fprintf(w, "    %s\n", line); err != nil {
+				return err
+			}
+		}
+		_, err := fmt.Fprintln(w)
+		return err
+	}
+	return nil
+}
+
+// countByRule returns the number of issues reported by each rule.
+func countByRule(issues []Issue) map[string]int {
+	counts := make(map[string]int)
+	for _, issue := range issues {
+		counts[issue.Rule]++
+	}
+	return counts
+}
+
+// dumpRules writes the rule registry in the given format.
+func dumpRules(w io.Writer, format string) error {
+	if format != "json" {
+		return fmt.Errorf("invalid -dump-rules format: %s. Valid options are: json", format)
+	}
+
+	jsonBytes, err := json.MarshalIndent(rules, "", "  ")
+	if err != nil {
+		return fmt.Errorf("error marshaling rules: %w", err)
+	}
+
+	_, err = fmt.Fprintln(w, string(jsonBytes))
+	return err
+}
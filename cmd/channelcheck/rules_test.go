@@ -0,0 +1,126 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestDumpRules(t *testing.T) {
+	var stdout bytes.Buffer
+	if _, err := run([]string{"-dump-rules", "json"}, &stdout, &bytes.Buffer{}); err != nil {
+		t.Fatalf("run failed: %v", err)
+	}
+
+	var dumped []Rule
+	if err := json.Unmarshal(stdout.Bytes(), &dumped); err != nil {
+		t.Fatalf("dump is not a JSON rule array: %v\n%s", err, stdout.String())
+	}
+
+	byID := make(map[string]Rule)
+	for _, rule := range dumped {
+		if rule.ID == "" || rule.Title == "" || rule.Severity == "" || rule.Description == "" || rule.DocURL == "" || rule.Rationale == "" {
+			t.Errorf("rule has empty fields: %+v", rule)
+		}
+		if severityRank(rule.Severity) == 0 {
+			t.Errorf("rule %s has invalid severity %q", rule.ID, rule.Severity)
+		}
+		byID[rule.ID] = rule
+	}
+
+	for _, id := range []string{
+		RuleSendWithoutSelect,
+		RuleUnbufferedChannel,
+		RuleTimeAfterInLoop,
+		RuleSendUnderLock,
+		RuleSpinningDrain,
+		RuleLargeBuffer,
+		RuleIndexedSend,
+		RuleBusyRetry,
+		RuleSendAfterClose,
+		RuleReceiveInLoopCond,
+		RuleSingleCaseSelect,
+		RuleConditionalMake,
+		RuleProducerClose,
+		RuleSilentDrop,
+		RuleInvertedCommaOk,
+		RuleUnreachableChannel,
+		RuleSendInRecover,
+		RuleLogOnlyDefault,
+		RuleRangeSignal,
+		RuleReturnedUnbuffered,
+		RuleUncoordinatedFanIn,
+		RuleUnusedReceive,
+		RuleCapturedReassignedChan,
+		RuleNilCheckFallthrough,
+		RuleUnusedChanParam,
+		RuleSendContainsChan,
+		RuleSiblingClose,
+		RuleReaderLeak,
+		RuleFuncChan,
+		RuleNilReceiverSend,
+		RuleLenBuffer,
+		RuleContextChan,
+		RuleMainDeadlock,
+		RuleAtomicMix,
+		RuleLoopVarCapture,
+		RuleUnusedSuppression,
+		RuleOverCapacity,
+		RuleUnreadErrors,
+		RuleNilFieldSend,
+		RuleDropHandler,
+		RuleHandlerSend,
+		RuleParseError,
+		RuleChanAsSlice,
+		RuleClosedRecvOperand,
+		RuleOnceBlocking,
+		RuleImmediateClose,
+		RulePackageChan,
+		RuleUnawaitedReply,
+		RuleSendBeforeGo,
+		RuleChanOfChan,
+		RuleShadowedCaseSend,
+	} {
+		if _, ok := byID[id]; !ok {
+			t.Errorf("rule %s missing from dump", id)
+		}
+	}
+}
+
+func TestDumpRules_InvalidFormat(t *testing.T) {
+	if _, err := run([]string{"-dump-rules", "yaml"}, &bytes.Buffer{}, &bytes.Buffer{}); err == nil {
+		t.Errorf("expected an error for an unsupported format")
+	}
+}
+
+func TestExplain(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "a.go", `package test
+func send(ch chan int) {
+	ch <- 1
+}
+`)
+	rationale := "    An unbuffered send completes only when a receiver takes the value"
+
+	var terse bytes.Buffer
+	if _, err := run([]string{"-path", dir}, &terse, &bytes.Buffer{}); err != nil {
+		t.Fatalf("run failed: %v", err)
+	}
+	if strings.Contains(terse.String(), rationale) {
+		t.Errorf("rationale printed without -explain:\n%s", terse.String())
+	}
+
+	var explained bytes.Buffer
+	if _, err := run([]string{"-path", dir, "-explain"}, &explained, &bytes.Buffer{}); err != nil {
+		t.Fatalf("run failed: %v", err)
+	}
+	if !strings.Contains(explained.String(), rationale) {
+		t.Errorf("rationale missing with -explain:\n%s", explained.String())
+	}
+
+	if _, err := run([]string{"-path", filepath.Join(dir, "a.go"), "-explain", "-output", "json"}, &bytes.Buffer{}, &bytes.Buffer{}); err == nil {
+		t.Errorf("expected an error for -explain with -output=json")
+	}
+}
@@ -0,0 +1,117 @@
+package main
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// externalRules is a centrally managed rule configuration loaded with
+// -rules-from. It is applied on top of -preset and beneath -enable,
+// -disable, -warn-as-error and an explicit -buffer-bytes-threshold.
+type externalRules struct {
+	Enable               []string          `json:"enable"`
+	Disable              []string          `json:"disable"`
+	Severity             map[string]string `json:"severity"`
+	BufferBytesThreshold *int64            `json:"buffer_bytes_threshold"`
+}
+
+// rulesCacheTTL is how long a -rules-from document fetched over HTTP is
+// reused before it is fetched again.
+var rulesCacheTTL = 5 * time.Minute
+
+// rulesCacheDir returns the directory that -rules-from documents fetched
+// over HTTP are cached in. Tests replace it.
+var rulesCacheDir = func() (string, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "channelcheck"), nil
+}
+
+// loadExternalRules reads the -rules-from document at source, an http(s)
+// URL or a file path.
+func loadExternalRules(source string) (*externalRules, error) {
+	var data []byte
+	var err error
+	if strings.HasPrefix(source, "http://") || strings.HasPrefix(source, "https://") {
+		data, err = fetchRules(source)
+	} else {
+		data, err = os.ReadFile(source)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error loading -rules-from: %w", err)
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	var external externalRules
+	if err := decoder.Decode(&external); err != nil {
+		return nil, fmt.Errorf("error parsing -rules-from %s: %w", source, err)
+	}
+	return &external, nil
+}
+
+// fetchRules returns the body of url, from the cache if it was fetched less
+// than rulesCacheTTL ago. The cache is best effort: if it cannot be read or
+// written, the document is fetched every time.
+func fetchRules(url string) ([]byte, error) {
+	var cached string
+	if dir, err := rulesCacheDir(); err == nil {
+		sum := sha256.Sum256([]byte(url))
+		cached = filepath.Join(dir, "rules-"+hex.EncodeToString(sum[:8])+".json")
+		if info, err := os.Stat(cached); err == nil && time.Since(info.ModTime()) < rulesCacheTTL {
+			if data, err := os.ReadFile(cached); err == nil {
+				return data, nil
+			}
+		}
+	}
+
+	client := &http.Client{Timeout: 10 * time.Second}
+	resp, err := client.Get(url)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("fetching %s: %s", url, resp.Status)
+	}
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("fetching %s: %w", url, err)
+	}
+
+	if cached != "" && os.MkdirAll(filepath.Dir(cached), 0o755) == nil {
+		_ = os.WriteFile(cached, data, 0o644)
+	}
+	return data, nil
+}
+
+// apply merges the enablement and severities into config.
+func (e *externalRules) apply(config ruleConfig) error {
+	if err := config.setEnabled(strings.Join(e.Enable, ","), true); err != nil {
+		return fmt.Errorf("invalid enable: %w", err)
+	}
+	if err := config.setEnabled(strings.Join(e.Disable, ","), false); err != nil {
+		return fmt.Errorf("invalid disable: %w", err)
+	}
+	for id, severity := range e.Severity {
+		severity = strings.ToUpper(severity)
+		if severityRank(severity) == 0 {
+			return fmt.Errorf("invalid severity %q for rule %s: valid options are INFO, WARNING, ERROR", severity, id)
+		}
+		if err := config.setSeverity([]string{id}, severity); err != nil {
+			return fmt.Errorf("invalid severity: %w", err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,112 @@
+package main
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+const externalRulesJSON = `{
+	"enable": ["len-buffer"],
+	"disable": ["unbuffered-channel"],
+	"severity": {"send-without-select": "error"},
+	"buffer_bytes_threshold": 4096
+}`
+
+func TestParseFlags_RulesFromFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "rules.json")
+	if err := os.WriteFile(path, []byte(externalRulesJSON), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	opts, err := parseFlags([]string{"-rules-from", path}, &bytes.Buffer{})
+	if err != nil {
+		t.Fatalf("parseFlags failed: %v", err)
+	}
+	if !opts.config.enabled[RuleLenBuffer] || opts.config.enabled[RuleUnbufferedChannel] {
+		t.Errorf("got enabled %v, want len-buffer on and unbuffered-channel off", opts.config.enabled)
+	}
+	if got := opts.config.severity[RuleSendWithoutSelect]; got != "ERROR" {
+		t.Errorf("got severity %q for send-without-select, want ERROR", got)
+	}
+	if opts.bufferBytesThreshold != 4096 {
+		t.Errorf("got buffer threshold %d, want 4096", opts.bufferBytesThreshold)
+	}
+
+	// Local flags take precedence over the document.
+	opts, err = parseFlags([]string{"-rules-from", path, "-enable", RuleUnbufferedChannel, "-buffer-bytes-threshold", "100"}, &bytes.Buffer{})
+	if err != nil {
+		t.Fatalf("parseFlags failed: %v", err)
+	}
+	if !opts.config.enabled[RuleUnbufferedChannel] {
+		t.Errorf("-enable did not override the -rules-from document")
+	}
+	if opts.bufferBytesThreshold != 100 {
+		t.Errorf("got buffer threshold %d, want the flag's 100", opts.bufferBytesThreshold)
+	}
+}
+
+func TestParseFlags_RulesFromInvalid(t *testing.T) {
+	dir := t.TempDir()
+	for name, doc := range map[string]string{
+		"unknown rule":     `{"enable": ["no-such-rule"]}`,
+		"unknown field":    `{"enabled": ["len-buffer"]}`,
+		"invalid severity": `{"severity": {"len-buffer": "fatal"}}`,
+	} {
+		t.Run(name, func(t *testing.T) {
+			path := filepath.Join(dir, name+".json")
+			if err := os.WriteFile(path, []byte(doc), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := parseFlags([]string{"-rules-from", path}, &bytes.Buffer{}); err == nil {
+				t.Errorf("expected an error for %s", doc)
+			}
+		})
+	}
+}
+
+func TestParseFlags_RulesFromURL(t *testing.T) {
+	cacheDir := t.TempDir()
+	oldDir := rulesCacheDir
+	rulesCacheDir = func() (string, error) { return cacheDir, nil }
+	t.Cleanup(func() { rulesCacheDir = oldDir })
+
+	requests := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Write([]byte(externalRulesJSON))
+	}))
+	defer srv.Close()
+
+	for range 2 {
+		opts, err := parseFlags([]string{"-rules-from", srv.URL + "/rules.json"}, &bytes.Buffer{})
+		if err != nil {
+			t.Fatalf("parseFlags failed: %v", err)
+		}
+		if !opts.config.enabled[RuleLenBuffer] {
+			t.Errorf("len-buffer not enabled by the fetched document")
+		}
+	}
+	if requests != 1 {
+		t.Errorf("got %d requests, want 1 with the second load served from the cache", requests)
+	}
+
+	oldTTL := rulesCacheTTL
+	rulesCacheTTL = 0
+	t.Cleanup(func() { rulesCacheTTL = oldTTL })
+	if _, err := parseFlags([]string{"-rules-from", srv.URL + "/rules.json"}, &bytes.Buffer{}); err != nil {
+		t.Fatalf("parseFlags failed: %v", err)
+	}
+	if requests != 2 {
+		t.Errorf("got %d requests, want 2 once the cache expired", requests)
+	}
+
+	missing := httptest.NewServer(http.NotFoundHandler())
+	defer missing.Close()
+	if _, err := parseFlags([]string{"-rules-from", missing.URL}, &bytes.Buffer{}); err == nil {
+		t.Errorf("expected an error for a 404 response")
+	}
+}
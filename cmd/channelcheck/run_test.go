@@ -0,0 +1,398 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestRun_StatusStderr(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "a.go", `package test
+func send() {
+	ch := make(chan int)
+	ch <- 1
+}
+`)
+
+	var stdout, stderr bytes.Buffer
+	if _, err := run([]string{"-path", dir, "-output", "json", "-status-stderr"}, &stdout, &stderr); err != nil {
+		t.Fatalf("run failed: %v", err)
+	}
+
+	var output JSONOutput
+	if err := json.Unmarshal(stdout.Bytes(), &output); err != nil {
+		t.Fatalf("stdout is not the JSON report: %v\n%s", err, stdout.String())
+	}
+	if output.Total != 2 {
+		t.Errorf("got %d issues in report, want 2", output.Total)
+	}
+
+	var status RunStatus
+	if err := json.Unmarshal(stderr.Bytes(), &status); err != nil {
+		t.Fatalf("stderr is not the status JSON: %v\n%s", err, stderr.String())
+	}
+	if want := (RunStatus{Issues: 2, Errors: 0, Failed: false}); status != want {
+		t.Errorf("got status %+v, want %+v", status, want)
+	}
+}
+
+func TestRun_StatusStderrOnFailure(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	missing := filepath.Join(t.TempDir(), "missing.go")
+	if _, err := run([]string{"-path", missing, "-status-stderr"}, &stdout, &stderr); err == nil {
+		t.Fatalf("expected run to fail for a missing path")
+	}
+
+	if stdout.Len() != 0 {
+		t.Errorf("expected no report on stdout, got %q", stdout.String())
+	}
+
+	var status RunStatus
+	if err := json.Unmarshal(stderr.Bytes(), &status); err != nil {
+		t.Fatalf("stderr is not the status JSON: %v\n%s", err, stderr.String())
+	}
+	if want := (RunStatus{Issues: 0, Errors: 1, Failed: true}); status != want {
+		t.Errorf("got status %+v, want %+v", status, want)
+	}
+}
+
+func TestRun_Timeout(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"a.go", "b.go", "c.go"} {
+		writeFile(t, dir, name, `package test
+func send(ch chan int) {
+	ch <- 1
+}
+`)
+	}
+
+	// Stall on b.go until the deadline passes, so a.go and b.go are
+	// analyzed and c.go is not.
+	analyzeFileHook = func(ctx context.Context, path string) {
+		if filepath.Base(path) == "b.go" {
+			<-ctx.Done()
+		}
+	}
+	t.Cleanup(func() { analyzeFileHook = nil })
+
+	var stdout, stderr bytes.Buffer
+	if _, err := run([]string{"-path", dir, "-output", "json", "-timeout", "50ms"}, &stdout, &stderr); err != nil {
+		t.Fatalf("run failed: %v", err)
+	}
+
+	var output JSONOutput
+	if err := json.Unmarshal(stdout.Bytes(), &output); err != nil {
+		t.Fatalf("failed to parse output: %v", err)
+	}
+	if output.Total != 2 {
+		t.Errorf("got %d issues, want the 2 from a.go and b.go", output.Total)
+	}
+	if got := strings.TrimSpace(stderr.String()); got != "analysis timed out after 50ms" {
+		t.Errorf("got stderr %q, want the timeout note", got)
+	}
+}
+
+func TestRun_Profiles(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "a.go", `package test
+func send(ch chan int) {
+	ch <- 1
+}
+`)
+	profiles := t.TempDir()
+	cpuProfile := filepath.Join(profiles, "cpu.pprof")
+	memProfile := filepath.Join(profiles, "mem.pprof")
+
+	var stdout bytes.Buffer
+	if _, err := run([]string{"-path", dir, "-cpuprofile", cpuProfile, "-memprofile", memProfile}, &stdout, &bytes.Buffer{}); err != nil {
+		t.Fatalf("run failed: %v", err)
+	}
+	if !strings.Contains(stdout.String(), "channel send without select") {
+		t.Errorf("report missing from stdout: %q", stdout.String())
+	}
+
+	for _, path := range []string{cpuProfile, memProfile} {
+		info, err := os.Stat(path)
+		if err != nil {
+			t.Errorf("profile not written: %v", err)
+			continue
+		}
+		if info.Size() == 0 {
+			t.Errorf("%s is empty", filepath.Base(path))
+		}
+	}
+}
+
+func TestRun_Tests(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "worker.go", `package test
+func send(ch chan int) {
+	ch <- 1
+}
+`)
+	writeFile(t, dir, "worker_test.go", `package test
+func sendTest(ch chan int) {
+	ch <- 1
+}
+`)
+
+	tests := []struct {
+		mode string
+		want []string
+	}{
+		{mode: "include", want: []string{"worker.go", "worker_test.go"}},
+		{mode: "exclude", want: []string{"worker.go"}},
+		{mode: "only", want: []string{"worker_test.go"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.mode, func(t *testing.T) {
+			var stdout bytes.Buffer
+			if _, err := run([]string{"-path", dir, "-output", "json", "-tests", tt.mode}, &stdout, &bytes.Buffer{}); err != nil {
+				t.Fatalf("run failed: %v", err)
+			}
+
+			var output JSONOutput
+			if err := json.Unmarshal(stdout.Bytes(), &output); err != nil {
+				t.Fatalf("failed to parse output: %v", err)
+			}
+			var got []string
+			for _, issue := range output.Issues {
+				got = append(got, filepath.Base(issue.Position.Filename))
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("got issues in %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	if _, err := run([]string{"-path", dir, "-tests", "some"}, &bytes.Buffer{}, &bytes.Buffer{}); err == nil {
+		t.Errorf("expected an error for an invalid -tests value")
+	}
+}
+
+func TestRun_JSONFile(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "a.go", `package test
+func send(ch chan int) {
+	ch <- 1
+}
+`)
+	jsonFile := filepath.Join(t.TempDir(), "report.json")
+
+	var stdout bytes.Buffer
+	if _, err := run([]string{"-path", dir, "-json-file", jsonFile}, &stdout, &bytes.Buffer{}); err != nil {
+		t.Fatalf("run failed: %v", err)
+	}
+	if !strings.HasPrefix(stdout.String(), "Found 1 potential issues:") {
+		t.Errorf("stdout is not the text report: %q", stdout.String())
+	}
+
+	data, err := os.ReadFile(jsonFile)
+	if err != nil {
+		t.Fatalf("JSON report not written: %v", err)
+	}
+	var output JSONOutput
+	if err := json.Unmarshal(data, &output); err != nil {
+		t.Fatalf("JSON report is invalid: %v\n%s", err, data)
+	}
+	if output.Total != 1 || output.Issues[0].Rule != RuleSendWithoutSelect {
+		t.Errorf("got %+v, want the send-without-select issue", output.Issues)
+	}
+}
+
+func TestRun_RenderFrom(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "a.go", `package test
+func send() {
+	ch := make(chan int)
+	ch <- 1
+}
+`)
+	var report, text bytes.Buffer
+	if _, err := run([]string{"-path", dir, "-output", "json"}, &report, &bytes.Buffer{}); err != nil {
+		t.Fatalf("run failed: %v", err)
+	}
+	if _, err := run([]string{"-path", dir}, &text, &bytes.Buffer{}); err != nil {
+		t.Fatalf("run failed: %v", err)
+	}
+	reportPath := writeFile(t, t.TempDir(), "report.json", report.String())
+
+	// Remove the source to show the report is rendered without analysis.
+	if err := os.RemoveAll(dir); err != nil {
+		t.Fatal(err)
+	}
+
+	var rendered bytes.Buffer
+	if _, err := run([]string{"-render-from", reportPath}, &rendered, &bytes.Buffer{}); err != nil {
+		t.Fatalf("run failed: %v", err)
+	}
+	if rendered.String() != text.String() {
+		t.Errorf("got text:\n%s\nwant:\n%s", rendered.String(), text.String())
+	}
+
+	rendered.Reset()
+	if _, err := run([]string{"-render-from", reportPath, "-output", "sarif"}, &rendered, &bytes.Buffer{}); err != nil {
+		t.Fatalf("run failed: %v", err)
+	}
+	var log sarifLog
+	if err := json.Unmarshal(rendered.Bytes(), &log); err != nil {
+		t.Fatalf("output is not SARIF: %v\n%s", err, rendered.String())
+	}
+	var output JSONOutput
+	if err := json.Unmarshal(report.Bytes(), &output); err != nil {
+		t.Fatalf("failed to parse report: %v", err)
+	}
+	results := log.Runs[0].Results
+	if len(results) != output.Total {
+		t.Fatalf("got %d SARIF results, want %d", len(results), output.Total)
+	}
+	for i, issue := range output.Issues {
+		result := results[i]
+		if result.RuleID != issue.Rule || result.Message.Text != issue.Message ||
+			result.PartialFingerprints["channelcheck/v1"] != issue.Fingerprint ||
+			result.Locations[0].PhysicalLocation.Region.StartLine != issue.Position.StartLine {
+			t.Errorf("result %d: got %+v, want %+v", i, result, issue)
+		}
+	}
+}
+
+func TestRun_MaxDepth(t *testing.T) {
+	dir := t.TempDir()
+	const src = `package test
+func send(ch chan int) {
+	ch <- 1
+}
+`
+	writeFile(t, dir, "root.go", src)
+	writeFile(t, dir, filepath.Join("a", "one.go"), src)
+	writeFile(t, dir, filepath.Join("a", "b", "two.go"), src)
+
+	tests := []struct {
+		depth string
+		want  []string
+	}{
+		{depth: "0", want: []string{"root.go"}},
+		{depth: "1", want: []string{"one.go", "root.go"}},
+		{depth: "-1", want: []string{"one.go", "root.go", "two.go"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.depth, func(t *testing.T) {
+			var stdout bytes.Buffer
+			if _, err := run([]string{"-path", dir, "-output", "json", "-max-depth", tt.depth}, &stdout, &bytes.Buffer{}); err != nil {
+				t.Fatalf("run failed: %v", err)
+			}
+
+			var output JSONOutput
+			if err := json.Unmarshal(stdout.Bytes(), &output); err != nil {
+				t.Fatalf("failed to parse output: %v", err)
+			}
+			var got []string
+			for _, issue := range output.Issues {
+				got = append(got, filepath.Base(issue.Position.Filename))
+			}
+			slices.Sort(got)
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("got issues in %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRun_ReportEmpty(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "clean.go", `package test
+func add(a, b int) int {
+	return a + b
+}
+`)
+
+	tests := []struct {
+		name     string
+		args     []string
+		expected string
+	}{
+		{name: "txt", args: nil, expected: "No issues found!\n"},
+		{name: "txt without empty report", args: []string{"-report-empty=false"}, expected: ""},
+		{name: "json", args: []string{"-output", "json"}, expected: "{\n  \"issues\": [],\n  \"total\": 0\n}\n"},
+		{name: "json without empty report", args: []string{"-output", "json", "-report-empty=false"}, expected: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var stdout bytes.Buffer
+			if _, err := run(append([]string{"-path", dir}, tt.args...), &stdout, &bytes.Buffer{}); err != nil {
+				t.Fatalf("run failed: %v", err)
+			}
+			if stdout.String() != tt.expected {
+				t.Errorf("got output %q, want %q", stdout.String(), tt.expected)
+			}
+		})
+	}
+
+	// Issues are still reported with -report-empty=false.
+	writeFile(t, dir, "send.go", `package test
+func send(ch chan int) {
+	ch <- 1
+}
+`)
+	var stdout bytes.Buffer
+	if _, err := run([]string{"-path", dir, "-report-empty=false"}, &stdout, &bytes.Buffer{}); err != nil {
+		t.Fatalf("run failed: %v", err)
+	}
+	if !strings.Contains(stdout.String(), "Found 1 potential issues") {
+		t.Errorf("report missing with issues present:\n%s", stdout.String())
+	}
+}
+
+func TestRun_Stdin(t *testing.T) {
+	source := `package test
+func send(ch chan int) {
+	ch <- 1
+}
+`
+	old := stdin
+	t.Cleanup(func() { stdin = old })
+
+	tests := []struct {
+		name     string
+		args     []string
+		filename string
+	}{
+		{name: "default filename", args: nil, filename: "<stdin>"},
+		{name: "custom filename", args: []string{"-stdin-filename", "internal/queue/send.go"}, filename: "internal/queue/send.go"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stdin = strings.NewReader(source)
+			var stdout bytes.Buffer
+			args := append([]string{"-path", "-", "-output", "json"}, tt.args...)
+			if _, err := run(args, &stdout, &bytes.Buffer{}); err != nil {
+				t.Fatalf("run failed: %v", err)
+			}
+
+			var output JSONOutput
+			if err := json.Unmarshal(stdout.Bytes(), &output); err != nil {
+				t.Fatalf("failed to parse output: %v", err)
+			}
+			if output.Total != 1 {
+				t.Fatalf("got %d issues, want 1", output.Total)
+			}
+			if got := output.Issues[0].Position.Filename; got != tt.filename {
+				t.Errorf("got filename %q, want %q", got, tt.filename)
+			}
+		})
+	}
+
+	if _, err := run([]string{"-path", "-", "-fix"}, &bytes.Buffer{}, &bytes.Buffer{}); err == nil {
+		t.Errorf("expected an error for -path=- with -fix")
+	}
+}
@@ -0,0 +1,144 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+)
+
+// SARIF 2.1.0 types, limited to the properties channelcheck fills in.
+type (
+	sarifLog struct {
+		Version string     `json:"version"`
+		Schema  string     `json:"$schema"`
+		Runs    []sarifRun `json:"runs"`
+	}
+
+	sarifRun struct {
+		Tool    sarifTool     `json:"tool"`
+		Results []sarifResult `json:"results"`
+	}
+
+	sarifTool struct {
+		Driver sarifDriver `json:"driver"`
+	}
+
+	sarifDriver struct {
+		Name           string      `json:"name"`
+		InformationURI string      `json:"informationUri"`
+		Rules          []sarifRule `json:"rules"`
+	}
+
+	sarifRule struct {
+		ID                   string             `json:"id"`
+		Name                 string             `json:"name"`
+		ShortDescription     sarifMessage       `json:"shortDescription"`
+		FullDescription      sarifMessage       `json:"fullDescription"`
+		HelpURI              string             `json:"helpUri"`
+		DefaultConfiguration sarifConfiguration `json:"defaultConfiguration"`
+	}
+
+	sarifConfiguration struct {
+		Level string `json:"level"`
+	}
+
+	sarifMessage struct {
+		Text string `json:"text"`
+	}
+
+	sarifResult struct {
+		RuleID              string            `json:"ruleId,omitempty"`
+		Level               string            `json:"level"`
+		Message             sarifMessage      `json:"message"`
+		Locations           []sarifLocation   `json:"locations"`
+		PartialFingerprints map[string]string `json:"partialFingerprints"`
+	}
+
+	sarifLocation struct {
+		PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+	}
+
+	sarifPhysicalLocation struct {
+		ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+		Region           sarifRegion           `json:"region"`
+	}
+
+	sarifArtifactLocation struct {
+		URI string `json:"uri"`
+	}
+
+	sarifRegion struct {
+		StartLine   int `json:"startLine"`
+		StartColumn int `json:"startColumn"`
+		EndLine     int `json:"endLine"`
+		EndColumn   int `json:"endColumn"`
+	}
+)
+
+// sarifLevel maps a severity to a SARIF result level.
+func sarifLevel(severity string) string {
+	switch severity {
+	case "ERROR":
+		return "error"
+	case "WARNING":
+		return "warning"
+	default:
+		return "note"
+	}
+}
+
+// printSARIF writes the issues as a SARIF 2.1.0 log with the rule registry
+// as the tool's rules, for code scanning dashboards.
+func printSARIF(w io.Writer, issues []Issue) error {
+	driver := sarifDriver{
+		Name:           "channelcheck",
+		InformationURI: "https://github.com/johnsaigle/channelcheck",
+		Rules:          make([]sarifRule, len(rules)),
+	}
+	for i, rule := range rules {
+		driver.Rules[i] = sarifRule{
+			ID:                   rule.ID,
+			Name:                 rule.Title,
+			ShortDescription:     sarifMessage{Text: rule.Title},
+			FullDescription:      sarifMessage{Text: rule.Description},
+			HelpURI:              rule.DocURL,
+			DefaultConfiguration: sarifConfiguration{Level: sarifLevel(rule.Severity)},
+		}
+	}
+
+	results := make([]sarifResult, len(issues))
+	for i, issue := range issues {
+		p := issue.Pos
+		results[i] = sarifResult{
+			RuleID:  issue.Rule,
+			Level:   sarifLevel(issue.Severity),
+			Message: sarifMessage{Text: issue.Message},
+			Locations: []sarifLocation{{
+				PhysicalLocation: sarifPhysicalLocation{
+					ArtifactLocation: sarifArtifactLocation{URI: filepath.ToSlash(p.Filename)},
+					Region: sarifRegion{
+						StartLine:   p.StartLine,
+						StartColumn: p.StartColumn,
+						EndLine:     p.EndLine,
+						EndColumn:   p.EndColumn,
+					},
+				},
+			}},
+			PartialFingerprints: map[string]string{"channelcheck/v1": issue.Fingerprint()},
+		}
+	}
+
+	log := sarifLog{
+		Version: "2.1.0",
+		Schema:  "https://json.schemastore.org/sarif-2.1.0.json",
+		Runs:    []sarifRun{{Tool: sarifTool{Driver: driver}, Results: results}},
+	}
+	jsonBytes, err := json.MarshalIndent(log, "", "  ")
+	if err != nil {
+		return fmt.Errorf("error marshaling SARIF: %w", err)
+	}
+
+	_, err = fmt.Fprintln(w, string(jsonBytes))
+	return err
+}
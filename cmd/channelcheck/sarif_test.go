@@ -0,0 +1,35 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+)
+
+func TestPrintSARIF(t *testing.T) {
+	var buf bytes.Buffer
+	if err := printSARIF(&buf, sampleIssues); err != nil {
+		t.Fatalf("printSARIF failed: %v", err)
+	}
+
+	var log sarifLog
+	if err := json.Unmarshal(buf.Bytes(), &log); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if log.Version != "2.1.0" || len(log.Runs) != 1 {
+		t.Fatalf("unexpected log: %+v", log)
+	}
+
+	driver, results := log.Runs[0].Tool.Driver, log.Runs[0].Results
+	if len(driver.Rules) != len(rules) {
+		t.Errorf("got %d rules, want the %d in the registry", len(driver.Rules), len(rules))
+	}
+	if len(results) != len(sampleIssues) {
+		t.Fatalf("got %d results, want %d", len(results), len(sampleIssues))
+	}
+	for i, issue := range sampleIssues {
+		if got, want := results[i].Level, sarifLevel(issue.Severity); got != want {
+			t.Errorf("result %d: got level %q, want %q", i, got, want)
+		}
+	}
+}
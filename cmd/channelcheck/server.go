@@ -0,0 +1,131 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"maps"
+	"net/http"
+	"os"
+	"os/signal"
+	"runtime"
+	"time"
+)
+
+const (
+	// maxRequestBytes caps the size of a single /analyze request body.
+	maxRequestBytes = 10 << 20
+	// shutdownTimeout bounds how long in-flight requests get to finish on SIGINT.
+	shutdownTimeout = 5 * time.Second
+)
+
+// AnalyzeRequest is the body accepted by POST /analyze
+type AnalyzeRequest struct {
+	Filename string `json:"filename"`
+	Source   string `json:"source"`
+}
+
+// newServer returns the HTTP handler for the analysis API. Sources are
+// analyzed with the rule options in opts and the issues whose fingerprints
+// are in suppressed are left out, as the CLI does for -ignore-file and
+// -baseline. A source that does not parse is reported as a parse-error
+// issue. At most maxConcurrent analyses run at the same time; other requests
+// wait for a free slot or give up when their context is cancelled.
+func newServer(opts *options, suppressed map[string]bool, maxConcurrent int) http.Handler {
+	sem := make(chan struct{}, maxConcurrent)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/analyze", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			w.Header().Set("Allow", http.MethodPost)
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		select {
+		case sem <- struct{}{}:
+			defer func() { <-sem }()
+		case <-r.Context().Done():
+			http.Error(w, "request cancelled", http.StatusServiceUnavailable)
+			return
+		}
+
+		var req AnalyzeRequest
+		if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxRequestBytes)).Decode(&req); err != nil {
+			http.Error(w, fmt.Sprintf("error decoding request: %v", err), http.StatusBadRequest)
+			return
+		}
+		if req.Filename == "" {
+			req.Filename = "input.go"
+		}
+
+		analyzer := newAnalyzer(opts)
+		if err := analyzer.analyzeSource(req.Filename, []byte(req.Source)); err != nil && !analyzer.recordParseError(err) {
+			http.Error(w, fmt.Sprintf("error analyzing source: %v", err), http.StatusUnprocessableEntity)
+			return
+		}
+		issues := filterIgnored(analyzer.issues, suppressed)
+
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(newJSONOutput(issues)); err != nil {
+			log.Printf("error writing response: %v", err)
+		}
+	})
+	return mux
+}
+
+// serverSuppressions returns the fingerprints the server leaves out: those
+// in the -ignore-file and in the -baseline.
+func serverSuppressions(opts *options) (map[string]bool, error) {
+	ignored, baseline, err := loadSuppressions(opts)
+	if err != nil {
+		return nil, err
+	}
+	suppressed := make(map[string]bool, len(ignored)+len(baseline))
+	maps.Copy(suppressed, ignored)
+	maps.Copy(suppressed, baseline)
+	return suppressed, nil
+}
+
+// serve runs the analysis API on the -serve address until SIGINT, then shuts
+// down gracefully.
+func serve(opts *options) error {
+	suppressed, err := serverSuppressions(opts)
+	if err != nil {
+		return err
+	}
+
+	addr := opts.serve
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	srv := &http.Server{
+		Addr:              addr,
+		Handler:           newServer(opts, suppressed, runtime.NumCPU()),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	errCh := make(chan error, 1)
+	go func() {
+		log.Printf("Listening on %s", addr)
+		errCh <- srv.ListenAndServe()
+	}()
+
+	select {
+	case err := <-errCh:
+		return fmt.Errorf("error serving: %w", err)
+	case <-ctx.Done():
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		return fmt.Errorf("error shutting down server: %w", err)
+	}
+	if err := <-errCh; err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return fmt.Errorf("error serving: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,131 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// newTestServer starts the analysis API configured by the command line args.
+func newTestServer(t *testing.T, maxConcurrent int, args ...string) *httptest.Server {
+	t.Helper()
+	opts, err := parseFlags(args, &bytes.Buffer{})
+	if err != nil {
+		t.Fatalf("parseFlags failed: %v", err)
+	}
+	suppressed, err := serverSuppressions(opts)
+	if err != nil {
+		t.Fatalf("serverSuppressions failed: %v", err)
+	}
+
+	srv := httptest.NewServer(newServer(opts, suppressed, maxConcurrent))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+// postAnalyze sends source to the /analyze endpoint of srv and decodes the
+// report.
+func postAnalyze(t *testing.T, srv *httptest.Server, filename, source string) JSONOutput {
+	t.Helper()
+	body, err := json.Marshal(AnalyzeRequest{Filename: filename, Source: source})
+	if err != nil {
+		t.Fatalf("failed to marshal request: %v", err)
+	}
+	resp, err := http.Post(srv.URL+"/analyze", "application/json", bytes.NewReader(body))
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("got status %d, want %d", resp.StatusCode, http.StatusOK)
+	}
+	var output JSONOutput
+	if err := json.NewDecoder(resp.Body).Decode(&output); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	return output
+}
+
+func TestServer_Analyze(t *testing.T) {
+	srv := newTestServer(t, 2)
+
+	output := postAnalyze(t, srv, "example.go", `package test
+func bad() {
+	ch := make(chan int)
+	ch <- 1
+}
+`)
+
+	if output.Total != 2 || len(output.Issues) != 2 {
+		t.Fatalf("got %d issues (total %d), want 2", len(output.Issues), output.Total)
+	}
+	for _, issue := range output.Issues {
+		if issue.Position.Filename != "example.go" {
+			t.Errorf("got filename %q, want %q", issue.Position.Filename, "example.go")
+		}
+	}
+	if !strings.Contains(output.Issues[1].Message, "channel send without select") {
+		t.Errorf("unexpected second issue: %q", output.Issues[1].Message)
+	}
+}
+
+func TestServer_Options(t *testing.T) {
+	const source = `package test
+func bad() {
+	ch := make(chan int)
+	ch <- 1
+}
+`
+	defaults := postAnalyze(t, newTestServer(t, 1), "example.go", source)
+	if defaults.Total != 2 {
+		t.Fatalf("got %d issues with the defaults, want 2", defaults.Total)
+	}
+	var send JSONIssue
+	for _, issue := range defaults.Issues {
+		if issue.Rule == RuleSendWithoutSelect {
+			send = issue
+		}
+	}
+
+	output := postAnalyze(t, newTestServer(t, 1, "-disable", RuleUnbufferedChannel, "-warn-as-error", RuleSendWithoutSelect), "example.go", source)
+	if output.Total != 1 || output.Issues[0].Rule != RuleSendWithoutSelect || output.Issues[0].Severity != "ERROR" {
+		t.Errorf("got %+v, want only the send as an ERROR", output.Issues)
+	}
+
+	ignoreFile := writeFile(t, t.TempDir(), "ignore.txt", send.Fingerprint+"\n")
+	output = postAnalyze(t, newTestServer(t, 1, "-ignore-file", ignoreFile), "example.go", source)
+	if output.Total != 1 || output.Issues[0].Rule != RuleUnbufferedChannel {
+		t.Errorf("got %+v, want the ignored send left out", output.Issues)
+	}
+}
+
+func TestServer_Errors(t *testing.T) {
+	srv := newTestServer(t, 1)
+
+	resp, err := http.Get(srv.URL + "/analyze")
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusMethodNotAllowed {
+		t.Errorf("GET: got status %d, want %d", resp.StatusCode, http.StatusMethodNotAllowed)
+	}
+
+	// As in the CLI, a source that does not parse is a parse-error issue.
+	output := postAnalyze(t, srv, "broken.go", "package test\nfunc {")
+	if output.Total != 1 || output.Issues[0].Rule != RuleParseError || output.Issues[0].Position.Filename != "broken.go" {
+		t.Errorf("invalid source: got %+v, want one parse-error", output.Issues)
+	}
+
+	resp, err = http.Post(srv.URL+"/analyze", "application/json", strings.NewReader("{"))
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("invalid request: got status %d, want %d", resp.StatusCode, http.StatusBadRequest)
+	}
+}
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// changedFiles lists the files below dir that differ between the git ref
+// since and the working tree, for -since, followed by the untracked files
+// that are not ignored, which are the newest code of all. Deleted files are
+// left out. Tests replace it.
+var changedFiles = func(dir, since string) ([]string, error) {
+	changed, err := gitFiles(dir, "diff", "--name-only", "-z", "--relative", "--diff-filter=d", since, "--", ".")
+	if err != nil {
+		return nil, fmt.Errorf("git diff against %s: %w", since, err)
+	}
+	untracked, err := gitFiles(dir, "ls-files", "-z", "--others", "--exclude-standard", "--", ".")
+	if err != nil {
+		return nil, fmt.Errorf("git ls-files: %w", err)
+	}
+	return append(changed, untracked...), nil
+}
+
+// gitFiles runs git with args in dir and returns the NUL-separated paths it
+// prints, which are relative to dir, joined to dir.
+func gitFiles(dir string, args ...string) ([]string, error) {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+
+	var files []string
+	for _, name := range strings.Split(string(out), "\x00") {
+		if name != "" {
+			files = append(files, filepath.Join(dir, filepath.FromSlash(name)))
+		}
+	}
+	return files, nil
+}
+
+// analyzeSince analyzes the Go files below the directory dir that changed
+// since the git ref since.
+func (a *Analyzer) analyzeSince(ctx context.Context, dir, since string) error {
+	info, err := os.Stat(dir)
+	if err != nil {
+		return fmt.Errorf("error accessing path: %w", err)
+	}
+	if !info.IsDir() {
+		return fmt.Errorf("-since needs -path to be a directory in a git repository, not %s", dir)
+	}
+
+	changed, err := changedFiles(dir, since)
+	if err != nil {
+		return err
+	}
+	var files []string
+	for _, file := range changed {
+		if !strings.HasSuffix(file, ".go") || !a.wantFile(file) {
+			continue
+		}
+		// A file deleted from the working tree has nothing left to analyze.
+		if _, err := os.Stat(file); errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		files = append(files, file)
+	}
+	return a.analyzeFiles(ctx, files)
+}
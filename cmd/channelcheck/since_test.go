@@ -0,0 +1,104 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+const sinceSource = `package test
+func send(ch chan int) {
+	ch <- 1
+}
+`
+
+// reportedFiles runs channelcheck with args and returns the base names of
+// the files it reported issues in.
+func reportedFiles(t *testing.T, args ...string) []string {
+	t.Helper()
+	var stdout bytes.Buffer
+	if _, err := run(append(args, "-output", "json"), &stdout, &bytes.Buffer{}); err != nil {
+		t.Fatalf("run failed: %v", err)
+	}
+	var output JSONOutput
+	if err := json.Unmarshal(stdout.Bytes(), &output); err != nil {
+		t.Fatalf("failed to parse output: %v", err)
+	}
+	var files []string
+	for _, issue := range output.Issues {
+		if name := filepath.Base(issue.Position.Filename); !slices.Contains(files, name) {
+			files = append(files, name)
+		}
+	}
+	slices.Sort(files)
+	return files
+}
+
+func TestRun_SinceMockedLister(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "changed.go", sinceSource)
+	writeFile(t, dir, "unchanged.go", sinceSource)
+
+	var gotRef string
+	old := changedFiles
+	changedFiles = func(d, since string) ([]string, error) {
+		gotRef = since
+		return []string{
+			filepath.Join(d, "changed.go"),
+			filepath.Join(d, "deleted.go"),
+			filepath.Join(d, "README.md"),
+		}, nil
+	}
+	t.Cleanup(func() { changedFiles = old })
+
+	if got := reportedFiles(t, "-path", dir, "-since", "origin/main"); !slices.Equal(got, []string{"changed.go"}) {
+		t.Errorf("got issues in %v, want only changed.go", got)
+	}
+	if gotRef != "origin/main" {
+		t.Errorf("lister got ref %q, want origin/main", gotRef)
+	}
+}
+
+func TestRun_SinceGitRepository(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	dir := t.TempDir()
+	git := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", append([]string{"-c", "user.name=test", "-c", "user.email=test@example.com"}, args...)...)
+		cmd.Dir = dir
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+
+	writeFile(t, dir, "old.go", sinceSource)
+	writeFile(t, dir, "gone.go", sinceSource)
+	git("init", "-q")
+	git("add", ".")
+	git("commit", "-q", "-m", "base")
+	git("tag", "base")
+
+	writeFile(t, dir, "new.go", sinceSource)
+	git("add", "new.go")
+	git("rm", "-q", "gone.go")
+	git("commit", "-q", "-m", "change")
+	writeFile(t, dir, "old.go", sinceSource+"\nfunc other() {}\n")
+
+	// Untracked files are included unless git ignores them.
+	writeFile(t, dir, "untracked.go", sinceSource)
+	writeFile(t, dir, "generated.go", sinceSource)
+	writeFile(t, dir, ".gitignore", "generated.go\n")
+
+	if got := reportedFiles(t, "-path", dir, "-since", "base"); !slices.Equal(got, []string{"new.go", "old.go", "untracked.go"}) {
+		t.Errorf("got issues in %v, want new.go, old.go and untracked.go", got)
+	}
+
+	if _, err := run([]string{"-path", dir, "-since", "no-such-ref"}, &bytes.Buffer{}, &bytes.Buffer{}); err == nil {
+		t.Errorf("expected an error for an unknown ref")
+	}
+}
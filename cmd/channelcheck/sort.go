@@ -0,0 +1,50 @@
+package main
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// defaultSort is the -sort order: deterministic source order.
+const defaultSort = "file,line,column"
+
+// issueComparators compare issues by a single -sort key. Severity sorts the
+// most severe issues first.
+var issueComparators = map[string]func(a, b Issue) int{
+	"file":     func(a, b Issue) int { return cmp.Compare(a.Pos.Filename, b.Pos.Filename) },
+	"line":     func(a, b Issue) int { return cmp.Compare(a.Pos.StartLine, b.Pos.StartLine) },
+	"column":   func(a, b Issue) int { return cmp.Compare(a.Pos.StartColumn, b.Pos.StartColumn) },
+	"severity": func(a, b Issue) int { return cmp.Compare(severityRank(b.Severity), severityRank(a.Severity)) },
+	"rule":     func(a, b Issue) int { return cmp.Compare(a.Rule, b.Rule) },
+	"message":  func(a, b Issue) int { return cmp.Compare(a.Message, b.Message) },
+}
+
+// parseSort builds a comparator from a comma-separated -sort key list. Each
+// key breaks ties left by the keys before it.
+func parseSort(keys string) (func(a, b Issue) int, error) {
+	var comparators []func(a, b Issue) int
+	for _, key := range strings.Split(keys, ",") {
+		key = strings.TrimSpace(key)
+		compare, ok := issueComparators[key]
+		if !ok {
+			valid := make([]string, 0, len(issueComparators))
+			for key := range issueComparators {
+				valid = append(valid, key)
+			}
+			slices.Sort(valid)
+			return nil, fmt.Errorf("invalid -sort key %q: valid keys are %s", key, strings.Join(valid, ", "))
+		}
+		comparators = append(comparators, compare)
+	}
+
+	return func(a, b Issue) int {
+		for _, compare := range comparators {
+			if c := compare(a, b); c != 0 {
+				return c
+			}
+		}
+		return 0
+	}, nil
+}
@@ -0,0 +1,48 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+	"testing"
+)
+
+func TestParseSort(t *testing.T) {
+	issues := []Issue{
+		{Rule: RuleUnbufferedChannel, Severity: "INFO", Pos: Position{Filename: "a.go", StartLine: 3}},
+		{Rule: RuleSendWithoutSelect, Severity: "WARNING", Pos: Position{Filename: "b.go", StartLine: 1}},
+		{Rule: RuleSendAfterClose, Severity: "ERROR", Pos: Position{Filename: "b.go", StartLine: 9}},
+		{Rule: RuleSendWithoutSelect, Severity: "WARNING", Pos: Position{Filename: "a.go", StartLine: 5}},
+	}
+
+	tests := []struct {
+		keys string
+		want []string
+	}{
+		{keys: defaultSort, want: []string{"a.go:3", "a.go:5", "b.go:1", "b.go:9"}},
+		{keys: "severity,file,line", want: []string{"b.go:9", "a.go:5", "b.go:1", "a.go:3"}},
+		{keys: "rule,line", want: []string{"b.go:9", "b.go:1", "a.go:5", "a.go:3"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.keys, func(t *testing.T) {
+			compare, err := parseSort(tt.keys)
+			if err != nil {
+				t.Fatalf("parseSort failed: %v", err)
+			}
+			sorted := slices.Clone(issues)
+			slices.SortStableFunc(sorted, compare)
+
+			var got []string
+			for _, issue := range sorted {
+				got = append(got, fmt.Sprintf("%s:%d", issue.Pos.Filename, issue.Pos.StartLine))
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	if _, err := parseSort("severity,author"); err == nil {
+		t.Errorf("expected an error for an unknown key")
+	}
+}
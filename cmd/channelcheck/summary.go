@@ -0,0 +1,153 @@
+package main
+
+import (
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+	"slices"
+)
+
+// Summary holds the aggregate counts of a run, printed at the end of the
+// text report by -summary-line and on their own by -summary-json.
+type Summary struct {
+	Total      int            `json:"total"`
+	BySeverity map[string]int `json:"by_severity"`
+	ByRule     map[string]int `json:"by_rule"`
+	Files      int            `json:"files"`
+}
+
+// summarize counts issues by severity and rule. files is the number of files
+// analyzed.
+func summarize(issues []Issue, files int) Summary {
+	summary := Summary{
+		Total:      len(issues),
+		BySeverity: map[string]int{"ERROR": 0, "WARNING": 0, "INFO": 0},
+		ByRule:     countByRule(issues),
+		Files:      files,
+	}
+	for _, issue := range issues {
+		summary.BySeverity[issue.Severity]++
+	}
+	return summary
+}
+
+func printSummaryJSON(w io.Writer, summary Summary) error {
+	jsonBytes, err := json.MarshalIndent(summary, "", "  ")
+	if err != nil {
+		return fmt.Errorf("error marshaling summary: %w", err)
+	}
+
+	_, err = fmt.Fprintln(w, string(jsonBytes))
+	return err
+}
+
+// printSeverityCounts writes the issue count by severity that ends a txt
+// report with -summary-line.
+func printSeverityCounts(w io.Writer, issues []Issue) error {
+	summary := summarize(issues, 0)
+	_, err := fmt.Fprintf(w, "%d errors, %d warnings, %d infos\n",
+		summary.BySeverity["ERROR"], summary.BySeverity["WARNING"], summary.BySeverity["INFO"])
+	return err
+}
+
+// ruleCount is the number of issues reported by a rule.
+type ruleCount struct {
+	Rule  string
+	Count int
+}
+
+// topRules returns the n rules with the most issues, most first. Ties are
+// ordered by rule ID.
+func topRules(issues []Issue, n int) []ruleCount {
+	var counts []ruleCount
+	for rule, count := range countByRule(issues) {
+		counts = append(counts, ruleCount{Rule: rule, Count: count})
+	}
+	slices.SortFunc(counts, func(a, b ruleCount) int {
+		return cmp.Or(cmp.Compare(b.Count, a.Count), cmp.Compare(a.Rule, b.Rule))
+	})
+	return counts[:min(n, len(counts))]
+}
+
+// printTopRules writes the -top-rules ranking.
+func printTopRules(w io.Writer, issues []Issue, n int) error {
+	if _, err := fmt.Fprintf(w, "Top %d rules:\n", n); err != nil {
+		return err
+	}
+	for _, count := range topRules(issues, n) {
+		if _, err := fmt.Fprintf(w, "  %-24s %d\n", count.Rule, count.Count); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// packageGroup is the issues reported in one package, identified by the
+// directory of its files.
+type packageGroup struct {
+	Dir     string
+	Package string
+	Issues  []Issue
+}
+
+// label names the group as its directory and package name.
+func (g packageGroup) label() string {
+	if g.Package == "" {
+		return g.Dir
+	}
+	return fmt.Sprintf("%s (package %s)", g.Dir, g.Package)
+}
+
+// groupByPackage groups issues by the directory of their file, a proxy for
+// the package. Groups are ordered by directory and keep the order of their
+// issues.
+func groupByPackage(issues []Issue) []packageGroup {
+	var groups []packageGroup
+	index := make(map[string]int)
+	for _, issue := range issues {
+		dir := filepath.ToSlash(filepath.Dir(issue.Pos.Filename))
+		i, ok := index[dir]
+		if !ok {
+			i = len(groups)
+			index[dir] = i
+			groups = append(groups, packageGroup{Dir: dir, Package: issue.Package})
+		}
+		groups[i].Issues = append(groups[i].Issues, issue)
+	}
+	slices.SortFunc(groups, func(a, b packageGroup) int {
+		return cmp.Compare(a.Dir, b.Dir)
+	})
+	return groups
+}
+
+// printModules writes the -modules report: the issue count of each package,
+// then each package's issues.
+func printModules(w io.Writer, issues []Issue) error {
+	if len(issues) == 0 {
+		_, err := fmt.Fprintln(w, "No issues found!")
+		return err
+	}
+
+	groups := groupByPackage(issues)
+	if _, err := fmt.Fprintln(w, "Issues by package:"); err != nil {
+		return err
+	}
+	for _, group := range groups {
+		if _, err := fmt.Fprintf(w, "  %-40s %d\n", group.label(), len(group.Issues)); err != nil {
+			return err
+		}
+	}
+	for _, group := range groups {
+		if _, err := fmt.Fprintf(w, "\n%s:\n", group.label()); err != nil {
+			return err
+		}
+		for _, issue := range group.Issues {
+			if _, err := fmt.Fprintf(w, "[%s] %s: %s\n", issue.Severity, issue.Pos, issue.Message); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
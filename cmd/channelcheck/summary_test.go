@@ -0,0 +1,201 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestRun_SummaryJSON(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "a.go", `package test
+func send() {
+	ch := make(chan int)
+	ch <- 1
+}
+`)
+	writeFile(t, dir, "b.go", `package test
+func other(ch chan int) {
+	ch <- 1
+}
+`)
+
+	var stdout bytes.Buffer
+	if _, err := run([]string{"-path", dir, "-summary-json"}, &stdout, &bytes.Buffer{}); err != nil {
+		t.Fatalf("run failed: %v", err)
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(stdout.Bytes(), &fields); err != nil {
+		t.Fatalf("output is not JSON: %v\n%s", err, stdout.String())
+	}
+	if _, ok := fields["issues"]; ok {
+		t.Errorf("summary includes individual issues")
+	}
+
+	var summary Summary
+	if err := json.Unmarshal(stdout.Bytes(), &summary); err != nil {
+		t.Fatalf("failed to parse summary: %v", err)
+	}
+	want := Summary{
+		Total:      3,
+		BySeverity: map[string]int{"ERROR": 0, "WARNING": 2, "INFO": 1},
+		ByRule:     map[string]int{RuleSendWithoutSelect: 2, RuleUnbufferedChannel: 1},
+		Files:      2,
+	}
+	if !reflect.DeepEqual(summary, want) {
+		t.Errorf("got %+v, want %+v", summary, want)
+	}
+}
+
+func TestRun_SummaryLine(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "a.go", `package test
+func send() {
+	ch := make(chan int)
+	ch <- 1
+}
+`)
+
+	report := func(args ...string) string {
+		t.Helper()
+		var stdout bytes.Buffer
+		if _, err := run(append([]string{"-path", dir}, args...), &stdout, &bytes.Buffer{}); err != nil {
+			t.Fatalf("run failed: %v", err)
+		}
+		return stdout.String()
+	}
+
+	const line = "0 errors, 1 warnings, 1 infos\n"
+	if got := report(); strings.Contains(got, "infos") {
+		t.Errorf("txt report has a summary line without -summary-line:\n%s", got)
+	}
+	if got := report("-summary-line"); !strings.HasSuffix(got, "\n\n"+line) {
+		t.Errorf("txt report does not end with %q:\n%s", line, got)
+	}
+	if got := report("-summary-line", "-output", "json"); strings.Contains(got, "infos") {
+		t.Errorf("json report has a summary line:\n%s", got)
+	}
+}
+
+func TestTopRules(t *testing.T) {
+	var issues []Issue
+	for rule, count := range map[string]int{
+		RuleSendWithoutSelect: 5,
+		RuleUnbufferedChannel: 2,
+		RuleBusyRetry:         2,
+		RuleLargeBuffer:       1,
+	} {
+		for range count {
+			issues = append(issues, Issue{Rule: rule})
+		}
+	}
+
+	want := []ruleCount{
+		{Rule: RuleSendWithoutSelect, Count: 5},
+		{Rule: RuleBusyRetry, Count: 2},
+		{Rule: RuleUnbufferedChannel, Count: 2},
+	}
+	if got := topRules(issues, 3); !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+	if got := topRules(issues, 10); len(got) != 4 {
+		t.Errorf("got %d rules, want all 4", len(got))
+	}
+}
+
+func TestRun_TopRules(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "a.go", `package test
+func send() {
+	ch := make(chan int)
+	ch <- 1
+	ch <- 2
+}
+`)
+
+	var stdout, stderr bytes.Buffer
+	if _, err := run([]string{"-path", dir, "-output", "json", "-top-rules", "1"}, &stdout, &stderr); err != nil {
+		t.Fatalf("run failed: %v", err)
+	}
+	if !json.Valid(stdout.Bytes()) {
+		t.Errorf("stdout is not the JSON report: %s", stdout.String())
+	}
+	want := "Top 1 rules:\n  send-without-select      2\n"
+	if stderr.String() != want {
+		t.Errorf("got stderr %q, want %q", stderr.String(), want)
+	}
+}
+
+func TestGroupByPackage(t *testing.T) {
+	issues := []Issue{
+		{Pos: Position{Filename: "internal/b/b.go", StartLine: 3}, Package: "b", Message: "b1"},
+		{Pos: Position{Filename: "internal/a/a.go", StartLine: 1}, Package: "a", Message: "a1"},
+		{Pos: Position{Filename: "internal/b/b_test.go", StartLine: 9}, Package: "b", Message: "b2"},
+		{Pos: Position{Filename: "internal/a/z.go", StartLine: 2}, Package: "a", Message: "a2"},
+		{Pos: Position{Filename: "internal/b/b.go", StartLine: 7}, Package: "b", Message: "b3"},
+	}
+
+	groups := groupByPackage(issues)
+	if len(groups) != 2 {
+		t.Fatalf("got %d groups, want 2: %+v", len(groups), groups)
+	}
+	want := []struct {
+		dir, pkg string
+		messages []string
+	}{
+		{"internal/a", "a", []string{"a1", "a2"}},
+		{"internal/b", "b", []string{"b1", "b2", "b3"}},
+	}
+	for i, w := range want {
+		g := groups[i]
+		var messages []string
+		for _, issue := range g.Issues {
+			messages = append(messages, issue.Message)
+		}
+		if g.Dir != w.dir || g.Package != w.pkg || !slices.Equal(messages, w.messages) {
+			t.Errorf("group %d: got %s with %v, want %s (package %s) with %v", i, g.Dir, messages, w.dir, w.pkg, w.messages)
+		}
+	}
+}
+
+func TestRun_Modules(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "worker/worker.go", `package worker
+func send(ch chan int) {
+	ch <- 1
+	ch <- 2
+}
+`)
+	writeFile(t, dir, "api/api.go", `package api
+func send(ch chan int) {
+	ch <- 1
+}
+`)
+
+	var stdout bytes.Buffer
+	if _, err := run([]string{"-path", dir, "-root", dir, "-modules"}, &stdout, &bytes.Buffer{}); err != nil {
+		t.Fatalf("run failed: %v", err)
+	}
+	got := stdout.String()
+	for _, want := range []string{
+		"Issues by package:\n  api (package api)                        1\n  worker (package worker)                  2\n",
+		"\napi (package api):\n[WARNING] api/api.go:3:2-9: ",
+		"\nworker (package worker):\n[WARNING] worker/worker.go:3:2-9: ",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("output missing %q:\n%s", want, got)
+		}
+	}
+
+	var report bytes.Buffer
+	if _, err := run([]string{"-path", dir, "-output", "json"}, &report, &bytes.Buffer{}); err != nil {
+		t.Fatalf("run failed: %v", err)
+	}
+	if !strings.Contains(report.String(), `"package": "worker"`) {
+		t.Errorf("JSON report is missing the package:\n%s", report.String())
+	}
+}
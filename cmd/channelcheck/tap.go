@@ -0,0 +1,34 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+)
+
+// printTAP writes the issues as a TAP version 13 stream: one failing test
+// point per issue, with the message and severity in a YAML diagnostic block.
+func printTAP(w io.Writer, issues []Issue) error {
+	if _, err := fmt.Fprintln(w, "TAP version 13"); err != nil {
+		return err
+	}
+	if len(issues) == 0 {
+		_, err := fmt.Fprintln(w, "1..0 # no issues")
+		return err
+	}
+
+	if _, err := fmt.Fprintf(w, "1..%d\n", len(issues)); err != nil {
+		return err
+	}
+	for i, issue := range issues {
+		rule := issue.Rule
+		if rule == "" {
+			rule = "channelcheck"
+		}
+		if _, err := fmt.Fprintf(w, "not ok %d - %s %s:%d\n  ---\n  message: %s\n  severity: %s\n  column: %d\n  ...\n",
+			i+1, rule, issue.Pos.Filename, issue.Pos.StartLine, strconv.Quote(issue.Message), issue.Severity, issue.Pos.StartColumn); err != nil {
+			return err
+		}
+	}
+	return nil
+}
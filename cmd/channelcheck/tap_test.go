@@ -0,0 +1,42 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestPrintTAP(t *testing.T) {
+	var buf bytes.Buffer
+	if err := printOutput(&buf, OutputFormatTAP, sampleIssues); err != nil {
+		t.Fatalf("printOutput failed: %v", err)
+	}
+
+	lines := strings.Split(buf.String(), "\n")
+	if lines[0] != "TAP version 13" || lines[1] != "1..2" {
+		t.Fatalf("got header %q, want the version and plan 1..2", lines[:2])
+	}
+	var failures []string
+	for _, line := range lines {
+		if strings.HasPrefix(line, "not ok ") {
+			failures = append(failures, line)
+		}
+	}
+	if len(failures) != len(sampleIssues) {
+		t.Fatalf("got %d not ok lines, want %d:\n%s", len(failures), len(sampleIssues), buf.String())
+	}
+	if want := "not ok 1 - send-without-select pkg/worker.go:15"; failures[0] != want {
+		t.Errorf("got %q, want %q", failures[0], want)
+	}
+	if want := `  message: "channel send without select statement may block indefinitely"`; !strings.Contains(buf.String(), want+"\n  severity: WARNING\n") {
+		t.Errorf("missing YAML diagnostics for the first issue:\n%s", buf.String())
+	}
+
+	buf.Reset()
+	if err := printTAP(&buf, nil); err != nil {
+		t.Fatalf("printTAP failed: %v", err)
+	}
+	if got, want := buf.String(), "TAP version 13\n1..0 # no issues\n"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,9 @@
+package corpus
+
+func produce() chan int {
+	ch := make(chan int)
+	go func() {
+		ch <- 1
+	}()
+	return ch
+}
@@ -0,0 +1,7 @@
+package sub
+
+func fanOut(chans []chan int) {
+	for i := range chans {
+		chans[i] <- i
+	}
+}
@@ -0,0 +1,25 @@
+package fixtures
+
+type events chan int
+
+func closeEvents(ch chan int) {
+	close(ch)
+}
+
+func assertedSendAfterClose() {
+	var ch any = make(chan int, 1)
+	closeEvents(ch.(chan int))
+	ch.(chan int) <- 1 // channelcheck: want "channel send without select" "already closed by closeEvents()"
+}
+
+func assertedSilentDrop() {
+	var ch any = make(chan int) // channelcheck: want "unbuffered channel creation"
+	select {
+	case ch.(chan int) <- 1: // channelcheck: want "may silently drop values"
+	default:
+	}
+}
+
+func assertedIndexedSend(chans map[string]any) {
+	chans["a"].(events) <- 1 // channelcheck: want "channel send without select" "from a collection"
+}
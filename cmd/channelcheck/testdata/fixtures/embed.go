@@ -0,0 +1,16 @@
+package fixtures
+
+import _ "embed"
+
+//go:embed sends.go
+var embeddedSource string
+
+func sendEmbedded(ch chan string) {
+	//go:generate echo unrelated directive
+	ch <- embeddedSource // channelcheck: want "channel send without select"
+}
+
+func suppressedAfterEmbed(ch chan string) {
+	//channelcheck:ignore
+	ch <- embeddedSource
+}
@@ -0,0 +1,34 @@
+package fixtures
+
+import "time"
+
+func spin(ch chan int) {
+	for {
+		<-ch // channelcheck: want "draining loop spins"
+	}
+}
+
+func drain(ch chan int) {
+	for range ch {
+	}
+}
+
+func perIterationTimeout(ch chan int) {
+	for {
+		select {
+		case <-ch:
+		case <-time.After(time.Second): // channelcheck: want "time.After inside loop"
+			return
+		}
+	}
+}
+
+func busyRetry(ch chan int) {
+	for {
+		select { // channelcheck: want "busy retry"
+		case ch <- 1:
+			return
+		default:
+		}
+	}
+}
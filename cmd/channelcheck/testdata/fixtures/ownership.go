@@ -0,0 +1,33 @@
+package fixtures
+
+type request struct {
+	payload string
+	reply   chan string
+}
+
+type envelope struct {
+	id    int
+	inner request
+}
+
+func sendRequest(requests chan request) {
+	reply := make(chan string, 1)
+	select {
+	case requests <- request{payload: "ping", reply: reply}: // channelcheck: want "transfers channel ownership"
+	default:
+	}
+}
+
+func forwardEnvelope(out chan envelope, e envelope) {
+	select {
+	case out <- e: // channelcheck: want "transfers channel ownership"
+	default:
+	}
+}
+
+func sendPlain(out chan string) {
+	select {
+	case out <- "no channels here":
+	default:
+	}
+}
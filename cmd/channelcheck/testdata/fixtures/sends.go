@@ -0,0 +1,25 @@
+package fixtures
+
+import "sync"
+
+func unguardedSend() {
+	ch := make(chan int) // channelcheck: want "unbuffered channel creation"
+	ch <- 1              // channelcheck: want "channel send without select"
+}
+
+func guardedSend(ch chan int) {
+	select {
+	case ch <- 1:
+	default:
+	}
+}
+
+func sendUnderLock(mu *sync.Mutex, ch chan int) {
+	mu.Lock()
+	defer mu.Unlock()
+	ch <- 1 // channelcheck: want "channel send without select" "while holding a lock"
+}
+
+func indexedSend(chans []chan int) {
+	chans[0] <- 1 // channelcheck: want "channel send without select" "from a collection"
+}
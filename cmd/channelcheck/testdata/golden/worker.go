@@ -0,0 +1,12 @@
+package worker
+
+func produce(n int) chan int {
+	out := make(chan int)
+	go func() {
+		for i := range n {
+			out <- i
+		}
+		close(out)
+	}()
+	return out
+}
@@ -0,0 +1,174 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+)
+
+// tuiPageSize is the number of issues -tui shows per page.
+const tuiPageSize = 20
+
+// tuiHelp lists the -tui commands.
+const tuiHelp = "n/enter next page, p previous, s SEVERITY or r RULE to filter (alone to clear), o N to open issue N, q to quit"
+
+// tuiModel is the state of the -tui browser: the collected issues, the
+// active filters and the page being shown. It does no terminal I/O, so it
+// can be tested on its own.
+type tuiModel struct {
+	issues   []Issue
+	severity string // only issues at this severity, or "" for all
+	rule     string // only issues from this rule, or "" for all
+	page     int
+	status   string // feedback from the last command
+}
+
+// visible returns the issues that pass the filters.
+func (m *tuiModel) visible() []Issue {
+	var visible []Issue
+	for _, issue := range m.issues {
+		if (m.severity == "" || issue.Severity == m.severity) && (m.rule == "" || issue.Rule == m.rule) {
+			visible = append(visible, issue)
+		}
+	}
+	return visible
+}
+
+// pages returns the number of pages of visible issues, at least 1.
+func (m *tuiModel) pages() int {
+	return max((len(m.visible())+tuiPageSize-1)/tuiPageSize, 1)
+}
+
+// render writes the current page. Issues are numbered across pages so
+// `o N` refers to the same issue whatever page is shown.
+func (m *tuiModel) render(w io.Writer) error {
+	visible := m.visible()
+	severity, rule := m.severity, m.rule
+	if severity == "" {
+		severity = "all"
+	}
+	if rule == "" {
+		rule = "all"
+	}
+	if _, err := fmt.Fprintf(w, "channelcheck: %d of %d issues (severity: %s, rule: %s) — page %d/%d\n\n",
+		len(visible), len(m.issues), severity, rule, m.page+1, m.pages()); err != nil {
+		return err
+	}
+
+	start := m.page * tuiPageSize
+	end := min(start+tuiPageSize, len(visible))
+	for i := start; i < end; i++ {
+		issue := visible[i]
+		if _, err := fmt.Fprintf(w, "%4d. [%s] %s: %s (%s)\n", i+1, issue.Severity, issue.Pos, issue.Message, issue.Rule); err != nil {
+			return err
+		}
+	}
+	if len(visible) == 0 {
+		if _, err := fmt.Fprintln(w, "      No issues match the filters."); err != nil {
+			return err
+		}
+	}
+
+	if _, err := fmt.Fprintf(w, "\n%s\n", tuiHelp); err != nil {
+		return err
+	}
+	if m.status != "" {
+		if _, err := fmt.Fprintln(w, m.status); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// update applies one command line. It returns the issue to open for `o N`
+// and whether the user asked to quit.
+func (m *tuiModel) update(line string) (open *Issue, quit bool) {
+	m.status = ""
+	fields := strings.Fields(line)
+	if len(fields) == 0 {
+		fields = []string{"n"}
+	}
+	arg := ""
+	if len(fields) > 1 {
+		arg = fields[1]
+	}
+
+	switch fields[0] {
+	case "q":
+		return nil, true
+	case "n":
+		if m.page+1 < m.pages() {
+			m.page++
+		}
+	case "p":
+		if m.page > 0 {
+			m.page--
+		}
+	case "s":
+		severity := strings.ToUpper(arg)
+		if severity != "" && severityRank(severity) == 0 {
+			m.status = fmt.Sprintf("unknown severity %q: use INFO, WARNING or ERROR", arg)
+			break
+		}
+		m.severity, m.page = severity, 0
+	case "r":
+		m.rule, m.page = arg, 0
+	case "o":
+		visible := m.visible()
+		n, err := strconv.Atoi(arg)
+		if err != nil || n < 1 || n > len(visible) {
+			m.status = fmt.Sprintf("no issue %q to open", arg)
+			break
+		}
+		return &visible[n-1], false
+	default:
+		m.status = fmt.Sprintf("unknown command %q", fields[0])
+	}
+	return nil, false
+}
+
+// openInEditor opens the file of an issue at its line in $EDITOR, vi by
+// default. Tests replace it.
+var openInEditor = func(issue Issue) error {
+	editor := os.Getenv("EDITOR")
+	if editor == "" {
+		editor = "vi"
+	}
+	cmd := exec.Command(editor, fmt.Sprintf("+%d", issue.Pos.StartLine), issue.Pos.Filename)
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = os.Stdin, os.Stdout, os.Stderr
+	return cmd.Run()
+}
+
+// runTUI browses issues interactively for -tui, reading one command per
+// line from in until `q` or the end of input.
+func runTUI(in io.Reader, out io.Writer, issues []Issue) error {
+	model := &tuiModel{issues: issues}
+	scanner := bufio.NewScanner(in)
+	for {
+		if err := model.render(out); err != nil {
+			return err
+		}
+		if _, err := fmt.Fprint(out, "> "); err != nil {
+			return err
+		}
+		if !scanner.Scan() {
+			_, err := fmt.Fprintln(out)
+			return errors.Join(err, scanner.Err())
+		}
+
+		issue, quit := model.update(scanner.Text())
+		if quit {
+			return nil
+		}
+		if issue != nil {
+			if err := openInEditor(*issue); err != nil {
+				model.status = fmt.Sprintf("error opening %s: %v", issue.Pos.Filename, err)
+			}
+		}
+	}
+}
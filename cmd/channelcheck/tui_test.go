@@ -0,0 +1,110 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestTUIModel_Render(t *testing.T) {
+	model := &tuiModel{issues: sampleIssues}
+
+	var out bytes.Buffer
+	if err := model.render(&out); err != nil {
+		t.Fatalf("render failed: %v", err)
+	}
+	for _, want := range []string{
+		"channelcheck: 2 of 2 issues (severity: all, rule: all) — page 1/1",
+		"   1. [WARNING] pkg/worker.go:15:2-9: channel send without select statement may block indefinitely (send-without-select)",
+		"   2. [INFO] pkg/worker.go:10:8-11:3: unbuffered channel creation detected",
+	} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("render output missing %q:\n%s", want, out.String())
+		}
+	}
+}
+
+func TestTUIModel_Update(t *testing.T) {
+	model := &tuiModel{issues: sampleIssues}
+
+	model.update("s info")
+	if visible := model.visible(); len(visible) != 1 || visible[0].Rule != RuleUnbufferedChannel {
+		t.Errorf("severity filter: got %v, want only the INFO issue", visible)
+	}
+	model.update("s")
+	model.update("r " + RuleSendWithoutSelect)
+	if visible := model.visible(); len(visible) != 1 || visible[0].Rule != RuleSendWithoutSelect {
+		t.Errorf("rule filter: got %v, want only the send-without-select issue", visible)
+	}
+	model.update("r")
+	if len(model.visible()) != 2 {
+		t.Errorf("clearing the filters should show every issue")
+	}
+
+	if issue, _ := model.update("o 2"); issue == nil || issue.Rule != RuleUnbufferedChannel {
+		t.Errorf("o 2: got %v, want the second issue", issue)
+	}
+	if issue, _ := model.update("o 3"); issue != nil || model.status == "" {
+		t.Errorf("o 3: got %v and status %q, want no issue and an error", issue, model.status)
+	}
+	model.update("s fatal")
+	if model.severity != "" || model.status == "" {
+		t.Errorf("invalid severity: got filter %q and status %q", model.severity, model.status)
+	}
+	if _, quit := model.update("q"); !quit {
+		t.Errorf("q did not quit")
+	}
+}
+
+func TestTUIModel_Pages(t *testing.T) {
+	var issues []Issue
+	for i := range tuiPageSize + 5 {
+		issues = append(issues, Issue{Rule: RuleSendWithoutSelect, Severity: "WARNING", Message: fmt.Sprintf("issue %d", i+1)})
+	}
+	model := &tuiModel{issues: issues}
+
+	model.update("")
+	var out bytes.Buffer
+	if err := model.render(&out); err != nil {
+		t.Fatalf("render failed: %v", err)
+	}
+	if !strings.Contains(out.String(), "page 2/2") || !strings.Contains(out.String(), "issue 25") || strings.Contains(out.String(), "issue 20 ") {
+		t.Errorf("second page rendered wrong:\n%s", out.String())
+	}
+	model.update("n")
+	if model.page != 1 {
+		t.Errorf("paged past the last page: %d", model.page)
+	}
+	model.update("p")
+	model.update("p")
+	if model.page != 0 {
+		t.Errorf("paged before the first page: %d", model.page)
+	}
+}
+
+func TestRunTUI(t *testing.T) {
+	var opened []Issue
+	old := openInEditor
+	openInEditor = func(issue Issue) error {
+		opened = append(opened, issue)
+		return nil
+	}
+	t.Cleanup(func() { openInEditor = old })
+
+	var out bytes.Buffer
+	if err := runTUI(strings.NewReader("s warning\no 1\nq\n"), &out, sampleIssues); err != nil {
+		t.Fatalf("runTUI failed: %v", err)
+	}
+	if len(opened) != 1 || opened[0].Rule != RuleSendWithoutSelect {
+		t.Errorf("got opened %v, want the send-without-select issue", opened)
+	}
+	if !strings.Contains(out.String(), "1 of 2 issues (severity: WARNING") {
+		t.Errorf("filtered page not rendered:\n%s", out.String())
+	}
+
+	// The end of input quits like q.
+	if err := runTUI(strings.NewReader(""), &bytes.Buffer{}, nil); err != nil {
+		t.Errorf("runTUI at end of input: %v", err)
+	}
+}
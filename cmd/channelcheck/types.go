@@ -0,0 +1,84 @@
+package main
+
+import (
+	"go/ast"
+	"go/importer"
+	"go/token"
+	"go/types"
+	"runtime"
+	"sync"
+)
+
+// lockedImporter serializes access to an importer so it can be shared by
+// every Analyzer, including concurrent requests in -serve mode.
+type lockedImporter struct {
+	mu  sync.Mutex
+	imp types.Importer
+}
+
+func (l *lockedImporter) Import(path string) (*types.Package, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.imp.Import(path)
+}
+
+// sharedImporter type-checks imported packages from source. Imported
+// packages are cached, so each one is only loaded once per process.
+var sharedImporter = &lockedImporter{
+	imp: importer.ForCompiler(token.NewFileSet(), "source", nil),
+}
+
+// sizes computes type sizes for the host architecture.
+var sizes = types.SizesFor("gc", runtime.GOARCH)
+
+// typeCheck collects type information for file. The file is checked on its
+// own, so references to other files in the package are unresolved; type
+// errors are ignored and checks must cope with missing information.
+func (a *Analyzer) typeCheck(file *ast.File) {
+	a.info = &types.Info{
+		Types: make(map[ast.Expr]types.TypeAndValue),
+		Defs:  make(map[*ast.Ident]types.Object),
+		Uses:  make(map[*ast.Ident]types.Object),
+
+		Selections: make(map[*ast.SelectorExpr]*types.Selection),
+	}
+
+	conf := types.Config{
+		Importer: sharedImporter,
+		Sizes:    sizes,
+		Error:    func(error) {},
+	}
+	// The returned error duplicates what Error already received.
+	_, _ = conf.Check(file.Name.Name, a.fset, []*ast.File{file}, a.info)
+}
+
+// typeOf returns the type of expr, or nil if it is unknown.
+func (a *Analyzer) typeOf(expr ast.Expr) types.Type {
+	if a.info == nil {
+		return nil
+	}
+	t := a.info.TypeOf(expr)
+	if t == nil || t == types.Typ[types.Invalid] {
+		return nil
+	}
+	return t
+}
+
+// chanOperand returns the channel expression behind a type assertion such as
+// ch.(chan int), so checks that track channel variables see through channels
+// passed around as any. Other expressions are returned without parentheses.
+func (a *Analyzer) chanOperand(expr ast.Expr) ast.Expr {
+	expr = ast.Unparen(expr)
+	assert, ok := expr.(*ast.TypeAssertExpr)
+	if !ok || assert.Type == nil {
+		return expr
+	}
+	if t := a.typeOf(assert); t != nil {
+		if _, ok := t.Underlying().(*types.Chan); !ok {
+			return expr
+		}
+	} else if _, ok := assert.Type.(*ast.ChanType); !ok {
+		return expr
+	}
+	return ast.Unparen(assert.X)
+}
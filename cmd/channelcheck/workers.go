@@ -0,0 +1,113 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"go/token"
+	"sync"
+)
+
+// releaseFileHook, if set, is called once a file's syntax tree is no longer
+// referenced by the analyzer. Tests use it to check how many files are held
+// at once.
+var releaseFileHook func(path string)
+
+// analyzeFiles analyzes the files at paths. With a.workers above 1, up to
+// that many files are parsed and analyzed at a time, each by its own
+// Analyzer with its own FileSet, so a file's syntax tree, type information
+// and positions are released as soon as its issues are collected. Only the
+// issues accumulate, which keeps memory bounded on very large trees. A file
+// that fails to parse is reported as a parse-error issue and does not stop
+// the run.
+func (a *Analyzer) analyzeFiles(ctx context.Context, paths []string) error {
+	if a.workers < 2 || a.fix {
+		for _, path := range paths {
+			if err := a.analyzeFile(ctx, path); err != nil && !a.recordParseError(err) {
+				return fmt.Errorf("error analyzing file %s: %w", path, err)
+			}
+		}
+		return nil
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	jobs := make(chan string)
+	var (
+		wg       sync.WaitGroup
+		errOnce  sync.Once
+		firstErr error
+	)
+	for range a.workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for path := range jobs {
+				worker := a.fork()
+				if err := worker.analyzeFile(ctx, path); err != nil && !worker.recordParseError(err) {
+					errOnce.Do(func() {
+						firstErr = fmt.Errorf("error analyzing file %s: %w", path, err)
+						cancel()
+					})
+					continue
+				}
+				a.merge(worker)
+			}
+		}()
+	}
+
+	queued := 0
+queue:
+	for _, path := range paths {
+		select {
+		case jobs <- path:
+			queued++
+		case <-ctx.Done():
+			break queue
+		}
+	}
+	close(jobs)
+	wg.Wait()
+
+	if firstErr != nil {
+		return firstErr
+	}
+	if queued < len(paths) {
+		// The caller's context ended before every file was handed out.
+		return ctx.Err()
+	}
+	return nil
+}
+
+// fork returns an Analyzer with the same settings as a and no state, to
+// analyze one file on its own.
+func (a *Analyzer) fork() *Analyzer {
+	return &Analyzer{
+		fset:                     token.NewFileSet(),
+		bufferBytesThreshold:     a.bufferBytesThreshold,
+		config:                   a.config,
+		logFuncs:                 a.logFuncs,
+		safeFuncs:                a.safeFuncs,
+		dropHandlers:             a.dropHandlers,
+		directive:                a.directive,
+		goVersion:                a.goVersion,
+		reportUnusedSuppressions: a.reportUnusedSuppressions,
+	}
+}
+
+// forFile returns an Analyzer that analyzes one file for a: it shares a's
+// settings and FileSet but collects the file's issues on its own.
+func (a *Analyzer) forFile() *Analyzer {
+	file := a.fork()
+	file.fset = a.fset
+	file.fix = a.fix
+	return file
+}
+
+// merge adds the issues and file count of a forked Analyzer to a.
+func (a *Analyzer) merge(worker *Analyzer) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.issues = append(a.issues, worker.issues...)
+	a.filesAnalyzed += worker.filesAnalyzed
+}
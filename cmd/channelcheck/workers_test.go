@@ -0,0 +1,82 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"sync"
+	"testing"
+)
+
+func TestRun_Workers(t *testing.T) {
+	dir := t.TempDir()
+	const files = 40
+	for i := range files {
+		writeFile(t, dir, fmt.Sprintf("f%02d.go", i), `package test
+func send() {
+	ch := make(chan int)
+	ch <- 1
+}
+`)
+	}
+
+	var (
+		mu                sync.Mutex
+		parsed, released  int
+		inFlight, maxLive int
+	)
+	analyzeFileHook = func(ctx context.Context, path string) {
+		mu.Lock()
+		defer mu.Unlock()
+		parsed++
+		inFlight++
+		maxLive = max(maxLive, inFlight)
+	}
+	releaseFileHook = func(path string) {
+		mu.Lock()
+		defer mu.Unlock()
+		released++
+		inFlight--
+	}
+	t.Cleanup(func() {
+		analyzeFileHook = nil
+		releaseFileHook = nil
+	})
+
+	report := func(args ...string) JSONOutput {
+		t.Helper()
+		var stdout bytes.Buffer
+		args = append([]string{"-path", dir, "-output", "json"}, args...)
+		if _, err := run(args, &stdout, &bytes.Buffer{}); err != nil {
+			t.Fatalf("run failed: %v", err)
+		}
+		var output JSONOutput
+		if err := json.Unmarshal(stdout.Bytes(), &output); err != nil {
+			t.Fatalf("failed to parse output: %v", err)
+		}
+		return output
+	}
+
+	sequential := report()
+	parsed, released, maxLive = 0, 0, 0
+	parallel := report("-workers", "4")
+
+	if parsed != files || released != files {
+		t.Errorf("parsed %d and released %d files, want %d", parsed, released, files)
+	}
+	if maxLive > 4 {
+		t.Errorf("held %d files at once, want at most 4", maxLive)
+	}
+	if parallel.Total != 2*files || !slices.Equal(parallel.Issues, sequential.Issues) {
+		t.Errorf("got %d issues with -workers, want the same %d as sequential", parallel.Total, sequential.Total)
+	}
+
+	if _, err := run([]string{"-path", dir, "-workers", "2", "-fix"}, &bytes.Buffer{}, &bytes.Buffer{}); err == nil {
+		t.Errorf("expected an error combining -workers and -fix")
+	}
+	if _, err := run([]string{"-path", dir, "-workers", "0"}, &bytes.Buffer{}, &bytes.Buffer{}); err == nil {
+		t.Errorf("expected an error for -workers 0")
+	}
+}